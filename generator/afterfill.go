@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AfterFiller is implemented by types which fix up their values once they are generated, such as to enforce
+// relationships between fields. Fill calls AfterFill after generating a value and everything within it, so that
+// hooks run bottom-up. It is not called for values set by Set or left zero.
+type AfterFiller interface {
+	AfterFill(r Randomiser) error
+}
+
+var afterFillerType = reflect.TypeOf((*AfterFiller)(nil)).Elem()
+
+// WithAfterFill registers a hook called with a pointer to each value of type T once it is generated, after any
+// AfterFill method of T. Hooks run bottom-up, in the order registered.
+func WithAfterFill[T any](fn func(p *T, r Randomiser) error) Option {
+	return func(g *generator) (*generator, error) {
+		rtype := reflect.TypeOf((*T)(nil)).Elem()
+		afterFills := make(map[reflect.Type][]func(p any, r Randomiser) error, len(g.afterFills)+1)
+		for key, value := range g.afterFills {
+			afterFills[key] = value
+		}
+		hooks := append([]func(p any, r Randomiser) error(nil), g.afterFills[rtype]...)
+		afterFills[rtype] = append(hooks, func(p any, r Randomiser) error {
+			return fn(p.(*T), r)
+		})
+		g.afterFills = afterFills
+		return g, nil
+	}
+}
+
+// afterFill calls the AfterFill method of a generated value, if it has one, and then the hooks registered for its
+// type.
+func (g *generator) afterFill(value reflect.Value, matcher *Matcher) error {
+	rtype := value.Type()
+	hooks := g.afterFills[rtype]
+	implements := reflect.PointerTo(rtype).Implements(afterFillerType)
+	if len(hooks) == 0 && !implements {
+		return nil
+	}
+	p := value.Addr().Interface()
+	if implements {
+		if err := recovered(func() error { return p.(AfterFiller).AfterFill(g) }); err != nil {
+			return fmt.Errorf("%s.AfterFill at %q: %w", rtype, matcher.Path(), err)
+		}
+	}
+	for _, hook := range hooks {
+		if err := recovered(func() error { return hook(p, g) }); err != nil {
+			return fmt.Errorf("WithAfterFill[%s] at %q: %w", rtype, matcher.Path(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package generator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type Line struct {
+	Quantity int
+	Price    int
+	Amount   int
+}
+
+func (l *Line) AfterFill(_ generator.Randomiser) error {
+	l.Amount = l.Quantity * l.Price
+	return nil
+}
+
+type Order struct {
+	StartAt int
+	EndAt   int
+	Lines   []Line
+	Count   int
+	Total   int
+}
+
+func TestAfterFill(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithAfterFill(func(o *Order, r generator.Randomiser) error {
+			o.Count = len(o.Lines)
+			o.Total = 0
+			for _, line := range o.Lines {
+				o.Total += line.Amount
+			}
+			if o.EndAt <= o.StartAt {
+				o.EndAt = o.StartAt + 1 + int(r.Uint32n(100))
+			}
+			return nil
+		}),
+	)
+	assert.Nil(t, err)
+
+	for i := 0; i < 20; i++ {
+		var o Order
+		assert.Nil(t, g.Fill(&o))
+		assert.Greater(t, o.EndAt, o.StartAt)
+		assert.Equal(t, len(o.Lines), o.Count)
+		total := 0
+		for _, line := range o.Lines {
+			assert.Equal(t, line.Quantity*line.Price, line.Amount)
+			total += line.Amount
+		}
+		assert.Equal(t, total, o.Total)
+	}
+
+	var order []string
+	g, err = generator.New().WithOptions(
+		generator.WithAfterFill(func(l *Line, _ generator.Randomiser) error {
+			order = append(order, "line")
+			return nil
+		}),
+		generator.WithAfterFill(func(o *Order, _ generator.Randomiser) error {
+			order = append(order, "order")
+			return nil
+		}),
+		generator.WithSliceLengthRange(1, 1),
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, g.Fill(&Order{}))
+	assert.Equal(t, []string{"line", "order"}, order)
+
+	failure := errors.New("inconsistent")
+	g, err = g.WithOptions(generator.WithAfterFill(func(l *Line, _ generator.Randomiser) error {
+		return failure
+	}))
+	assert.Nil(t, err)
+	err = g.Fill(&Order{})
+	assert.ErrorIs(t, err, failure)
+	assert.Contains(t, err.Error(), `WithAfterFill[generator_test.Line] at "Lines[0]"`)
+}
+
+type Fragile struct {
+	Lines []Line
+}
+
+func (f *Fragile) AfterFill(_ generator.Randomiser) error {
+	_ = f.Lines[len(f.Lines)]
+	return nil
+}
+
+func TestAfterFillPanics(t *testing.T) {
+	err := generator.New().Fill(&Fragile{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `generator_test.Fragile.AfterFill at "": panic: runtime error: index out of range`)
+
+	g, err := generator.New().WithOptions(generator.WithAfterFill(func(l *Line, _ generator.Randomiser) error {
+		panic("broken hook")
+	}))
+	assert.Nil(t, err)
+	err = g.Fill(&Order{}, generator.WithSliceLengthRange(1, 1))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `WithAfterFill[generator_test.Line] at "Lines[0]": panic: broken hook`)
+}
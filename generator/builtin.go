@@ -0,0 +1,314 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Values of some standard library types are only valid in particular forms, which filling their fields or bytes at
+// random would not produce, so fill generates them as follows, unless overridden by Set or a sample:
+//
+//   - the sql.Null types are valid with the ratio set by WithNullValidRatio, when their value is filled as usual;
+//   - json.RawMessage holds random valid JSON;
+//   - big.Int, big.Float and big.Rat have numbers of decimal digits set by WithDigits;
+//   - net.IP and netip.Addr hold IPv4 or IPv6 addresses, and netip.Prefix holds a masked prefix of one;
+//   - url.URL holds an http or https URL, and mail.Address holds a name and an address in an example domain;
+//   - time.Time is a UTC time between 2000 and 2030, to the second;
+//   - 16-byte arrays of a type named like UUID hold version 4 UUIDs;
+//   - regexp.Regexp is compiled from one of the patterns set by WithRegexpPatterns.
+type builtin func(g *generator, value reflect.Value, t *Matcher) error
+
+var builtins = map[reflect.Type]builtin{
+	reflect.TypeOf(json.RawMessage{}): fillRawMessage,
+	reflect.TypeOf(big.Int{}):         fillBigInt,
+	reflect.TypeOf(big.Float{}):       fillBigFloat,
+	reflect.TypeOf(big.Rat{}):         fillBigRat,
+	reflect.TypeOf(net.IP{}):          fillIP,
+	reflect.TypeOf(netip.Addr{}):      fillAddr,
+	reflect.TypeOf(netip.Prefix{}):    fillPrefix,
+	reflect.TypeOf(url.URL{}):         fillURL,
+	reflect.TypeOf(mail.Address{}):    fillMailAddress,
+	reflect.TypeOf(time.Time{}):       fillTime,
+	reflect.TypeOf(regexp.Regexp{}):   fillRegexp,
+}
+
+var defRegexps = []string{`^[a-z]+$`, `^\d{3}-\d{4}$`, `(?i)hello|world`, `^[A-Z][a-z]*( [A-Z][a-z]*)*$`}
+
+func builtinFor(rtype reflect.Type) (builtin, bool) {
+	if build, ok := builtins[rtype]; ok {
+		return build, true
+	}
+	switch {
+	case isNullType(rtype):
+		return fillNull, true
+	case isUUIDType(rtype):
+		return fillUUID, true
+	}
+	return nil, false
+}
+
+// WithNullValidRatio sets the probability of values of the sql.Null types being valid, where 0 means never and 1
+// means always
+func WithNullValidRatio(ratio float64) Option {
+	return recorded(ratioRule("WithNullValidRatio", kindLevel, nil, ratio, nullValidRules), func(c *Config) {
+		c.Ratios.NullValid = &ratio
+	})
+}
+
+// WithNullValidRatioFn registers a function for setting the chance of a value of an sql.Null type being valid.
+func WithNullValidRatioFn(fn func(t *Matcher) (float64, bool)) Option {
+	return ratioFnRule("WithNullValidRatioFn", noError(fn), nullValidRules)
+}
+
+func nullValidRules(g *generator) *ruleset[float64] {
+	return &g.nullValidRules
+}
+
+// WithDigits sets the range of the number of decimal digits in values of big.Int, big.Float and big.Rat
+func WithDigits(min, max int) Option {
+	return recorded(rangeRule("WithDigits", kindLevel, nil, digitsInt(min), digitsInt(max)), func(c *Config) {
+		c.setRange("digits", decimalOf(min), decimalOf(max))
+	})
+}
+
+// WithRegexpPatterns sets the patterns from which values of regexp.Regexp are compiled
+func WithRegexpPatterns(patterns ...string) Option {
+	return recorded(func(g *generator) (*generator, error) {
+		if len(patterns) == 0 {
+			return nil, fmt.Errorf("WithRegexpPatterns: patterns may not be empty")
+		}
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("WithRegexpPatterns: %w", err)
+			}
+		}
+		g.regexps = append([]string(nil), patterns...)
+		return g, nil
+	}, func(c *Config) {
+		c.Regexps = append([]string(nil), patterns...)
+	})
+}
+
+// isNullType reports whether rtype is one of the sql.Null types, which hold a value followed by a Valid flag.
+func isNullType(rtype reflect.Type) bool {
+	return rtype.Kind() == reflect.Struct && rtype.PkgPath() == "database/sql" &&
+		strings.HasPrefix(rtype.Name(), "Null") && rtype.NumField() == 2 && rtype.Field(1).Name == "Valid"
+}
+
+func isUUIDType(rtype reflect.Type) bool {
+	return rtype.Kind() == reflect.Array && rtype.Len() == 16 && rtype.Elem().Kind() == reflect.Uint8 &&
+		strings.Contains(strings.ToUpper(rtype.Name()), "UUID")
+}
+
+func fillNull(g *generator, value reflect.Value, t *Matcher) error {
+	rtype := value.Type()
+	ratio, err := g.resolveRatio(g.nullValidRules, defNullValidRatio, t.forSimpleType(rtype))
+	if err != nil {
+		return err
+	}
+	value.Set(reflect.Zero(rtype))
+	if !g.chanceTrue(ratio) {
+		return nil
+	}
+	if err := g.fill(value.Field(0), t.forField(value, 0)); err != nil {
+		return err
+	}
+	value.Field(1).SetBool(true)
+	return nil
+}
+
+func fillUUID(g *generator, value reflect.Value, _ *Matcher) error {
+	for i := 0; i < 16; i++ {
+		value.Index(i).SetUint(uint64(g.Uint32n(256)))
+	}
+	value.Index(6).SetUint(value.Index(6).Uint()&0x0f | 0x40)
+	value.Index(8).SetUint(value.Index(8).Uint()&0x3f | 0x80)
+	return nil
+}
+
+func fillRawMessage(g *generator, value reflect.Value, _ *Matcher) error {
+	raw, err := json.Marshal(g.randomJSON(2))
+	if err != nil {
+		return err
+	}
+	value.SetBytes(raw)
+	return nil
+}
+
+// randomJSON returns a random JSON value, nesting objects and arrays no deeper than depth.
+func (g *generator) randomJSON(depth int) any {
+	kinds := uint32(4)
+	if depth > 0 {
+		kinds = 6
+	}
+	switch g.Uint32n(kinds) {
+	case 0:
+		return nil
+	case 1:
+		return g.Uint32n(2) == 1
+	case 2:
+		return g.Float64() * defMaxFloat
+	case 3:
+		return g.word()
+	case 4:
+		object := make(map[string]any)
+		for i := g.Uint32n(4); i > 0; i-- {
+			object[g.word()] = g.randomJSON(depth - 1)
+		}
+		return object
+	}
+	array := make([]any, g.Uint32n(4))
+	for i := range array {
+		array[i] = g.randomJSON(depth - 1)
+	}
+	return array
+}
+
+// word returns a short random lower case word.
+func (g *generator) word() string {
+	letters := make([]byte, g.InclusiveUint32n(3, 8))
+	for i := range letters {
+		letters[i] = byte('a' + g.Uint32n(26))
+	}
+	return string(letters)
+}
+
+// digits returns a random string of decimal digits of a length set by WithDigits, with no leading zero.
+func (g *generator) digits(t *Matcher) (string, error) {
+	n, err := genNumeric[digitsInt](g, t)
+	if err != nil {
+		return "", err
+	}
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = byte('0' + g.Uint32n(10))
+	}
+	if out[0] == '0' {
+		out[0] = byte('1' + g.Uint32n(9))
+	}
+	return string(out), nil
+}
+
+func fillBigInt(g *generator, value reflect.Value, t *Matcher) error {
+	digits, err := g.digits(t.forSimpleType(value.Type()))
+	if err != nil {
+		return err
+	}
+	value.Addr().Interface().(*big.Int).SetString(digits, 10)
+	return nil
+}
+
+func fillBigFloat(g *generator, value reflect.Value, t *Matcher) error {
+	digits, err := g.digits(t.forSimpleType(value.Type()))
+	if err != nil {
+		return err
+	}
+	point := g.InclusiveUint32n(1, uint32(len(digits)))
+	f := value.Addr().Interface().(*big.Float)
+	// about 3.33 bits are needed per decimal digit
+	f.SetPrec(uint(len(digits))*4 + 8)
+	f.SetString(digits[:point] + "." + digits[point:] + "0")
+	return nil
+}
+
+func fillBigRat(g *generator, value reflect.Value, t *Matcher) error {
+	t = t.forSimpleType(value.Type())
+	numerator, err := g.digits(t)
+	if err != nil {
+		return err
+	}
+	denominator, err := g.digits(t)
+	if err != nil {
+		return err
+	}
+	value.Addr().Interface().(*big.Rat).SetString(numerator + "/" + denominator)
+	return nil
+}
+
+func (g *generator) randomAddr() netip.Addr {
+	if g.Uint32n(2) == 0 {
+		var a [4]byte
+		for i := range a {
+			a[i] = byte(g.Uint32n(256))
+		}
+		return netip.AddrFrom4(a)
+	}
+	var a [16]byte
+	for i := range a {
+		a[i] = byte(g.Uint32n(256))
+	}
+	return netip.AddrFrom16(a)
+}
+
+func fillIP(g *generator, value reflect.Value, _ *Matcher) error {
+	value.SetBytes(net.IP(g.randomAddr().AsSlice()))
+	return nil
+}
+
+func fillAddr(g *generator, value reflect.Value, _ *Matcher) error {
+	value.Set(reflect.ValueOf(g.randomAddr()))
+	return nil
+}
+
+func fillPrefix(g *generator, value reflect.Value, _ *Matcher) error {
+	addr := g.randomAddr()
+	prefix, err := addr.Prefix(int(g.Uint32n(uint32(addr.BitLen()) + 1)))
+	if err != nil {
+		return err
+	}
+	value.Set(reflect.ValueOf(prefix))
+	return nil
+}
+
+func fillURL(g *generator, value reflect.Value, _ *Matcher) error {
+	u := url.URL{Scheme: "https", Host: g.word() + ".example.com"}
+	if g.Uint32n(2) == 0 {
+		u.Scheme = "http"
+	}
+	for i := g.Uint32n(4); i > 0; i-- {
+		u.Path += "/" + g.word()
+	}
+	value.Set(reflect.ValueOf(u))
+	return nil
+}
+
+func fillMailAddress(g *generator, value reflect.Value, _ *Matcher) error {
+	first, last := g.word(), g.word()
+	value.Set(reflect.ValueOf(mail.Address{
+		Name:    strings.ToUpper(first[:1]) + first[1:] + " " + strings.ToUpper(last[:1]) + last[1:],
+		Address: first + "." + last + "@example.com",
+	}))
+	return nil
+}
+
+var (
+	minTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	maxTime = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+)
+
+func fillTime(g *generator, value reflect.Value, _ *Matcher) error {
+	value.Set(reflect.ValueOf(time.Unix(g.InclusiveInt64n(minTime, maxTime), 0).UTC()))
+	return nil
+}
+
+func fillRegexp(g *generator, value reflect.Value, _ *Matcher) error {
+	patterns := g.regexps
+	if len(patterns) == 0 {
+		patterns = defRegexps
+	}
+	re, err := regexp.Compile(patterns[g.Uint32n(uint32(len(patterns)))])
+	if err != nil {
+		return err
+	}
+	value.Set(reflect.ValueOf(re).Elem())
+	return nil
+}
@@ -0,0 +1,92 @@
+package generator_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type UUID [16]byte
+
+type Builtins struct {
+	Name     sql.NullString
+	Count    sql.NullInt64
+	At       sql.NullTime
+	Raw      json.RawMessage
+	Int      *big.Int
+	Float    big.Float
+	Rat      big.Rat
+	IP       net.IP
+	Addr     netip.Addr
+	Prefix   netip.Prefix
+	Link     url.URL
+	Mail     mail.Address
+	ID       UUID
+	Pattern  *regexp.Regexp
+	Checksum [16]byte
+}
+
+func TestBuiltins(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.WithNullValidRatio(1),
+		generator.WithDigits(30, 30),
+		generator.WithRegexpPatterns(`^\d+$`),
+	)
+	assert.Nil(t, err)
+
+	// a [16]byte which is not a UUID is filled as an array, so seldom has the version and variant bits of one
+	uuidShaped := 0
+	for i := 0; i < 20; i++ {
+		var b Builtins
+		assert.Nil(t, g.Fill(&b))
+		assert.True(t, b.Name.Valid)
+		assert.True(t, b.Count.Valid)
+		assert.True(t, b.At.Valid)
+		assert.False(t, b.At.Time.IsZero())
+		assert.True(t, json.Valid(b.Raw), string(b.Raw))
+		assert.Len(t, b.Int.String(), 30)
+		assert.True(t, b.Float.Prec() >= 100)
+		assert.Equal(t, 1, b.Rat.Sign())
+		assert.True(t, len(b.IP) == net.IPv4len || len(b.IP) == net.IPv6len)
+		assert.NotNil(t, b.IP.To16())
+		assert.True(t, b.Addr.IsValid())
+		assert.True(t, b.Prefix.IsValid())
+		assert.Equal(t, b.Prefix, b.Prefix.Masked())
+		parsed, err := url.Parse(b.Link.String())
+		assert.Nil(t, err)
+		assert.Equal(t, b.Link.Host, parsed.Host)
+		address, err := mail.ParseAddress(b.Mail.String())
+		assert.Nil(t, err)
+		assert.Equal(t, b.Mail.Address, address.Address)
+		assert.Equal(t, byte(0x40), b.ID[6]&0xf0)
+		assert.Equal(t, byte(0x80), b.ID[8]&0xc0)
+		assert.Equal(t, `^\d+$`, b.Pattern.String())
+		assert.True(t, b.At.Time.Before(time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)))
+		if b.Checksum[6]&0xf0 == 0x40 && b.Checksum[8]&0xc0 == 0x80 {
+			uuidShaped++
+		}
+	}
+	assert.Less(t, uuidShaped, 10)
+
+	g, err = generator.New().WithOptions(generator.WithNullValidRatio(0))
+	assert.Nil(t, err)
+	var b Builtins
+	assert.Nil(t, g.Fill(&b))
+	assert.Equal(t, sql.NullString{}, b.Name)
+
+	_, err = generator.New().WithOptions(generator.WithDigits(0, 3))
+	assert.NotNil(t, err)
+	_, err = generator.New().WithOptions(generator.WithRegexpPatterns(`(`))
+	assert.NotNil(t, err)
+}
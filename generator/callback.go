@@ -0,0 +1,77 @@
+package generator
+
+func noError[V any](fn func(t *Matcher) (V, bool)) func(t *Matcher) (V, bool, error) {
+	return func(t *Matcher) (V, bool, error) {
+		out, ok := fn(t)
+		return out, ok, nil
+	}
+}
+
+func lengthFnRule[T stringLenInt | sliceLenInt | mapLenInt](source string, fn func(t *Matcher) (int, int, bool, error)) Option {
+	return rangeFnRule(source, func(t *Matcher) (T, T, bool, error) {
+		min, max, ok, err := fn(t)
+		return T(min), T(max), ok, err
+	})
+}
+
+// WithPointerNilRatioFnE is like WithPointerNilRatioFn, but fn may return an error, which stops the fill and is
+// returned by Fill.
+func WithPointerNilRatioFnE(fn func(t *Matcher) (float64, bool, error)) Option {
+	return ratioFnRule("WithPointerNilRatioFnE", fn, pointerNilRules)
+}
+
+// WithSliceNilRatioFnE is like WithSliceNilRatioFn, but fn may return an error, which stops the fill and is
+// returned by Fill.
+func WithSliceNilRatioFnE(fn func(t *Matcher) (float64, bool, error)) Option {
+	return ratioFnRule("WithSliceNilRatioFnE", fn, sliceNilRules)
+}
+
+// WithMapNilRatioFnE is like WithMapNilRatioFn, but fn may return an error, which stops the fill and is returned by
+// Fill.
+func WithMapNilRatioFnE(fn func(t *Matcher) (float64, bool, error)) Option {
+	return ratioFnRule("WithMapNilRatioFnE", fn, mapNilRules)
+}
+
+// WithZeroRatioFnE is like WithZeroRatioFn, but fn may return an error, which stops the fill and is returned by Fill.
+func WithZeroRatioFnE(fn func(t *Matcher) (float64, bool, error)) Option {
+	return ratioFnRule("WithZeroRatioFnE", fn, zeroRules)
+}
+
+// WithBoolTrueRatioFnE is like WithBoolTrueRatioFn, but fn may return an error, which stops the fill and is
+// returned by Fill.
+func WithBoolTrueRatioFnE(fn func(t *Matcher) (float64, bool, error)) Option {
+	return ratioFnRule("WithBoolTrueRatioFnE", fn, boolTrueRules)
+}
+
+// WithRunesFnE is like WithRunesFn, but fn may return an error, which stops the fill and is returned by Fill.
+func WithRunesFnE(fn func(t *Matcher) ([]rune, bool, error)) Option {
+	return func(g *generator) (*generator, error) {
+		addRule(g, &g.runesRules, matchLevel, "WithRunesFnE", fn)
+		return g, nil
+	}
+}
+
+// WithStringFnE is like WithStringFn, but fn may return an error, which stops the fill and is returned by Fill.
+func WithStringFnE(fn func(t *Matcher) (string, bool, error)) Option {
+	return func(g *generator) (*generator, error) {
+		addRule(g, &g.stringRules, matchLevel, "WithStringFnE", fn)
+		return g, nil
+	}
+}
+
+// WithStringLengthFnE is like WithStringLengthFn, but fn may return an error, which stops the fill and is
+// returned by Fill.
+func WithStringLengthFnE(fn func(t *Matcher) (int, int, bool, error)) Option {
+	return lengthFnRule[stringLenInt]("WithStringLengthFnE", fn)
+}
+
+// WithSliceLengthFnE is like WithSliceLengthFn, but fn may return an error, which stops the fill and is
+// returned by Fill.
+func WithSliceLengthFnE(fn func(t *Matcher) (int, int, bool, error)) Option {
+	return lengthFnRule[sliceLenInt]("WithSliceLengthFnE", fn)
+}
+
+// WithMapLengthFnE is like WithMapLengthFn, but fn may return an error, which stops the fill and is returned by Fill.
+func WithMapLengthFnE(fn func(t *Matcher) (int, int, bool, error)) Option {
+	return lengthFnRule[mapLenInt]("WithMapLengthFnE", fn)
+}
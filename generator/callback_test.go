@@ -0,0 +1,85 @@
+package generator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallbackErrors(t *testing.T) {
+	failure := errors.New("no value available")
+
+	type scenario struct {
+		name     string
+		option   generator.Option
+		contains string
+	}
+	scenarios := []scenario{
+		{
+			name: "returned error",
+			option: generator.WithStringFnE(func(m *generator.Matcher) (string, bool, error) {
+				if m.FieldName() == "Street" {
+					return "", false, failure
+				}
+				return "", false, nil
+			}),
+			contains: `WithStringFnE #1 at "Address.Street": no value available`,
+		},
+		{
+			name: "inverted range",
+			option: generator.WithIntFn(func(m *generator.Matcher) (int, int, bool) {
+				return 10, 1, true
+			}),
+			contains: "min may not exceed max",
+		},
+		{
+			name: "invalid ratio",
+			option: generator.WithPointerNilRatioFn(func(m *generator.Matcher) (float64, bool) {
+				return 2, true
+			}),
+			contains: `at "Other": ratio must be in range 0 to 1`,
+		},
+		{
+			name: "empty runes",
+			option: generator.WithRunesFn(func(m *generator.Matcher) ([]rune, bool) {
+				return nil, true
+			}),
+			contains: "runes may not be empty",
+		},
+		{
+			name: "negative length",
+			option: generator.WithSliceLengthFnE(func(m *generator.Matcher) (int, int, bool, error) {
+				return -2, 2, true, nil
+			}),
+			contains: `at "Tags#len": length may not be negative`,
+		},
+		{
+			name: "panic",
+			option: generator.WithRangeFnE(func(m *generator.Matcher) (int, int, bool, error) {
+				var values []int
+				return values[m.Depth()], 0, true, nil
+			}),
+			contains: `WithRangeFnE[int] #1 at "Age": panic: runtime error: index out of range`,
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.name, func(tt *testing.T) {
+			subject, err := generator.New().WithOptions(s.option)
+			assert.Nil(tt, err)
+			err = subject.Fill(new(User))
+			assert.NotNil(tt, err)
+			assert.Contains(tt, err.Error(), s.contains)
+		})
+	}
+
+	subject, _ := generator.New().WithOptions(
+		generator.WithStringFnE(func(m *generator.Matcher) (string, bool, error) {
+			return "", false, failure
+		}),
+	)
+	assert.ErrorIs(t, subject.Fill(new(User)), failure)
+}
@@ -0,0 +1,464 @@
+package generator
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is a declarative description of generator settings. It may be loaded from a JSON or YAML document with
+// LoadConfig or WithConfigFile, and exported from a generator with its Config method. Settings made with callbacks
+// cannot be expressed declaratively, and are not exported.
+type Config struct {
+	// Seed seeds the default randomiser, so that a run can be replayed exactly.
+	Seed *uint64 `json:"seed,omitempty" yaml:"seed,omitempty"`
+	// Ranges sets the ranges of whole kinds, keyed by kind name, such as int or float64, or by one of string_length,
+	// slice_length, map_length and digits.
+	Ranges map[string]Range `json:"ranges,omitempty" yaml:"ranges,omitempty"`
+	Ratios Ratios           `json:"ratios,omitempty" yaml:"ratios,omitempty"`
+	// Runes sets the runes from which strings are constructed.
+	Runes string `json:"runes,omitempty" yaml:"runes,omitempty"`
+	// Regexps sets the patterns from which values of regexp.Regexp are compiled.
+	Regexps []string `json:"regexps,omitempty" yaml:"regexps,omitempty"`
+	// Sparse sets the number of fields of the struct being filled which are populated, as by the Sparse option.
+	Sparse *int   `json:"sparse,omitempty" yaml:"sparse,omitempty"`
+	Rules  []Rule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// Range is a closed interval. Its bounds must be represented exactly by the kind to which it applies.
+type Range struct {
+	Min Decimal `json:"min" yaml:"min"`
+	Max Decimal `json:"max" yaml:"max"`
+}
+
+// Decimal is a number in decimal notation, such as 42, -1.5 or 1e3. It is held as text, so that integers of every
+// kind are represented exactly, including those of 64-bit kinds beyond the precision of a float64. It is written to
+// and read from JSON and YAML as a number.
+type Decimal string
+
+func decimalOf[T numeric](v T) Decimal {
+	return Decimal(fmt.Sprint(v))
+}
+
+// decimalAs returns d as a value of type T, failing unless T represents it exactly, or, for floating point kinds,
+// to the nearest value within range.
+func decimalAs[T numeric](d Decimal) (T, error) {
+	var out T
+	rtype := reflect.TypeOf(out)
+	switch rtype.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(string(d), rtype.Bits())
+		if err != nil {
+			return out, fmt.Errorf("%q cannot be represented as %s", d, rtype.Kind())
+		}
+		return T(f), nil
+	}
+	r, ok := new(big.Rat).SetString(string(d))
+	if !ok || !r.IsInt() {
+		return out, fmt.Errorf("%q cannot be represented exactly as %s", d, rtype.Kind())
+	}
+	switch rtype.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(r.Num().String(), 10, rtype.Bits())
+		if err != nil {
+			return out, fmt.Errorf("%q is out of range for %s", d, rtype.Kind())
+		}
+		return T(i), nil
+	}
+	u, err := strconv.ParseUint(r.Num().String(), 10, rtype.Bits())
+	if err != nil {
+		return out, fmt.Errorf("%q is out of range for %s", d, rtype.Kind())
+	}
+	return T(u), nil
+}
+
+func (d Decimal) valid() error {
+	if _, ok := new(big.Rat).SetString(string(d)); !ok {
+		return fmt.Errorf("%q is not a decimal number", d)
+	}
+	return nil
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if err := d.valid(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(json.Number(d))
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*d = Decimal(n)
+	return d.valid()
+}
+
+func (d Decimal) MarshalYAML() (any, error) {
+	if err := d.valid(); err != nil {
+		return nil, err
+	}
+	tag := "!!float"
+	if _, err := strconv.ParseInt(string(d), 10, 64); err == nil {
+		tag = "!!int"
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: string(d)}, nil
+}
+
+func (d *Decimal) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: a number is required", node.Line)
+	}
+	*d = Decimal(node.Value)
+	return d.valid()
+}
+
+// Ratios sets probabilities, where 0 means never and 1 means always.
+type Ratios struct {
+	BoolTrue     *float64 `json:"bool_true,omitempty" yaml:"bool_true,omitempty"`
+	PointerNil   *float64 `json:"pointer_nil,omitempty" yaml:"pointer_nil,omitempty"`
+	SliceNil     *float64 `json:"slice_nil,omitempty" yaml:"slice_nil,omitempty"`
+	MapNil       *float64 `json:"map_nil,omitempty" yaml:"map_nil,omitempty"`
+	NullValid    *float64 `json:"null_valid,omitempty" yaml:"null_valid,omitempty"`
+	Zero         *float64 `json:"zero,omitempty" yaml:"zero,omitempty"`
+	PointerReuse *float64 `json:"pointer_reuse,omitempty" yaml:"pointer_reuse,omitempty"`
+}
+
+// Rule applies settings to values of a type, named as by reflect.Type.String such as main.Celsius, at a path, as
+// returned by Matcher.Path such as Orders[*].Address.Country, or to both where both are given. A '*' in a path
+// matches any sequence of characters. NilRatio applies to pointers, slices and maps alike.
+type Rule struct {
+	Type      string   `json:"type,omitempty" yaml:"type,omitempty"`
+	Path      string   `json:"path,omitempty" yaml:"path,omitempty"`
+	Range     *Range   `json:"range,omitempty" yaml:"range,omitempty"`
+	Length    *Range   `json:"length,omitempty" yaml:"length,omitempty"`
+	Values    []string `json:"values,omitempty" yaml:"values,omitempty"`
+	Runes     string   `json:"runes,omitempty" yaml:"runes,omitempty"`
+	NilRatio  *float64 `json:"nil_ratio,omitempty" yaml:"nil_ratio,omitempty"`
+	TrueRatio *float64 `json:"true_ratio,omitempty" yaml:"true_ratio,omitempty"`
+}
+
+// LoadConfig reads a JSON or YAML Config document, returning the equivalent options.
+func LoadConfig(r io.Reader) ([]Option, error) {
+	var c Config
+	br := bufio.NewReader(r)
+	if isJSON(br) {
+		decoder := json.NewDecoder(br)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&c); err != nil {
+			return nil, fmt.Errorf("LoadConfig: %w", err)
+		}
+	} else {
+		decoder := yaml.NewDecoder(br)
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&c); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("LoadConfig: %w", err)
+		}
+	}
+	return c.Options()
+}
+
+func isJSON(br *bufio.Reader) bool {
+	for {
+		r, _, err := br.ReadRune()
+		if err != nil {
+			return false
+		}
+		if !strings.ContainsRune(" \t\r\n", r) {
+			_ = br.UnreadRune()
+			return r == '{'
+		}
+	}
+}
+
+// WithConfigFile applies the settings in a JSON or YAML Config document read from path.
+func WithConfigFile(path string) Option {
+	return func(g *generator) (*generator, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("WithConfigFile: %w", err)
+		}
+		defer f.Close()
+		options, err := LoadConfig(f)
+		if err != nil {
+			return nil, err
+		}
+		return g.apply(options...)
+	}
+}
+
+// Config returns the declarative settings of the generator.
+func (g *generator) Config() Config {
+	return g.config.clone()
+}
+
+func (c Config) clone() Config {
+	if c.Seed != nil {
+		seed := *c.Seed
+		c.Seed = &seed
+	}
+	if c.Ranges != nil {
+		ranges := make(map[string]Range, len(c.Ranges))
+		for key, r := range c.Ranges {
+			ranges[key] = r
+		}
+		c.Ranges = ranges
+	}
+	c.Regexps = append([]string(nil), c.Regexps...)
+	c.Rules = append([]Rule(nil), c.Rules...)
+	return c
+}
+
+// Options returns the options equivalent to the Config.
+func (c Config) Options() ([]Option, error) {
+	var options []Option
+	if c.Seed != nil {
+		options = append(options, WithSeed(*c.Seed))
+	}
+	for key, r := range c.Ranges {
+		option, err := kindRange(key, r)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+	if c.Ratios.BoolTrue != nil {
+		options = append(options, WithBoolTrueRatio(*c.Ratios.BoolTrue))
+	}
+	if c.Ratios.PointerNil != nil {
+		options = append(options, WithPointerNilRatio(*c.Ratios.PointerNil))
+	}
+	if c.Ratios.SliceNil != nil {
+		options = append(options, WithSliceNilRatio(*c.Ratios.SliceNil))
+	}
+	if c.Ratios.MapNil != nil {
+		options = append(options, WithMapNilRatio(*c.Ratios.MapNil))
+	}
+	if c.Ratios.Zero != nil {
+		options = append(options, WithZeroRatio(*c.Ratios.Zero))
+	}
+	if c.Ratios.PointerReuse != nil {
+		options = append(options, WithPointerReuseRatio(*c.Ratios.PointerReuse))
+	}
+	if c.Ratios.NullValid != nil {
+		options = append(options, WithNullValidRatio(*c.Ratios.NullValid))
+	}
+	if c.Runes != "" {
+		options = append(options, WithRunes([]rune(c.Runes)))
+	}
+	if len(c.Regexps) != 0 {
+		options = append(options, WithRegexpPatterns(c.Regexps...))
+	}
+	if c.Sparse != nil {
+		options = append(options, Sparse(*c.Sparse))
+	}
+	for _, r := range c.Rules {
+		options = append(options, WithRule(r))
+	}
+	return options, nil
+}
+
+func kindRange(key string, r Range) (Option, error) {
+	var option Option
+	var err error
+	switch key {
+	case "string_length":
+		option, err = exactRange(r, WithStringLengthRange)
+	case "slice_length":
+		option, err = exactRange(r, WithSliceLengthRange)
+	case "map_length":
+		option, err = exactRange(r, WithMapLengthRange)
+	case "digits":
+		option, err = exactRange(r, WithDigits)
+	case "int":
+		option, err = exactRange(r, WithIntRange)
+	case "int8":
+		option, err = exactRange(r, WithInt8Range)
+	case "int16":
+		option, err = exactRange(r, WithInt16Range)
+	case "int32":
+		option, err = exactRange(r, WithInt32Range)
+	case "int64":
+		option, err = exactRange(r, WithInt64Range)
+	case "uint":
+		option, err = exactRange(r, WithUintRange)
+	case "uint8":
+		option, err = exactRange(r, WithUint8Range)
+	case "uint16":
+		option, err = exactRange(r, WithUint16Range)
+	case "uint32":
+		option, err = exactRange(r, WithUint32Range)
+	case "uint64":
+		option, err = exactRange(r, WithUint64Range)
+	case "float32":
+		option, err = exactRange(r, WithFloat32Range)
+	case "float64":
+		option, err = exactRange(r, WithFloat64Range)
+	default:
+		return nil, fmt.Errorf("LoadConfig: unknown range %q", key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("LoadConfig: range %q: %w", key, err)
+	}
+	return option, nil
+}
+
+// exactRange returns the option for a range, failing unless its bounds are represented exactly by T.
+func exactRange[T numeric](r Range, option func(min, max T) Option) (Option, error) {
+	min, err := decimalAs[T](r.Min)
+	if err != nil {
+		return nil, err
+	}
+	max, err := decimalAs[T](r.Max)
+	if err != nil {
+		return nil, err
+	}
+	return option(min, max), nil
+}
+
+// WithRule applies the settings of a declarative Rule. Rules for a path are more specific than rules for a type.
+func WithRule(r Rule) Option {
+	return func(g *generator) (*generator, error) {
+		if r.Type == "" && r.Path == "" {
+			return nil, fmt.Errorf("WithRule: a rule requires a type or a path")
+		}
+		source := fmt.Sprintf("WithRule(%s)", r)
+		spec := typeLevel
+		if r.Path != "" {
+			spec = fieldLevel
+		}
+		p := func(t *Matcher) bool {
+			return (r.Type == "" || t.rtype.String() == r.Type) && (r.Path == "" || pathMatches(r.Path, t.Path()))
+		}
+		var options []Option
+		if r.Range != nil {
+			ranges := declaredRanges(source, spec, p, *r.Range)
+			if len(ranges) == 0 {
+				return nil, fmt.Errorf("%s: range %s to %s cannot be represented exactly by any numeric kind", source, r.Range.Min, r.Range.Max)
+			}
+			options = append(options, ranges...)
+		}
+		if r.Length != nil {
+			min, err := decimalAs[int](r.Length.Min)
+			if err != nil {
+				return nil, fmt.Errorf("%s: length: %w", source, err)
+			}
+			max, err := decimalAs[int](r.Length.Max)
+			if err != nil {
+				return nil, fmt.Errorf("%s: length: %w", source, err)
+			}
+			options = append(options,
+				rangeRule(source, spec, p, stringLenInt(min), stringLenInt(max)),
+				rangeRule(source, spec, p, sliceLenInt(min), sliceLenInt(max)),
+				rangeRule(source, spec, p, mapLenInt(min), mapLenInt(max)),
+			)
+		}
+		if r.Runes != "" {
+			runes := []rune(r.Runes)
+			options = append(options, func(g *generator) (*generator, error) {
+				addRule(g, &g.runesRules, spec, source, func(t *Matcher) ([]rune, bool, error) {
+					return runes, p(t), nil
+				})
+				return g, nil
+			})
+		}
+		if len(r.Values) != 0 {
+			values := append([]string(nil), r.Values...)
+			options = append(options, func(g *generator) (*generator, error) {
+				addGeneratingRule(g, &g.stringRules, spec, source, func(g *generator, t *Matcher) (string, bool, error) {
+					if !p(t) {
+						return "", false, nil
+					}
+					return values[g.InclusiveUint64n(0, uint64(len(values)-1))], true, nil
+				})
+				return g, nil
+			})
+		}
+		if r.NilRatio != nil {
+			options = append(options,
+				ratioRule(source, spec, p, *r.NilRatio, pointerNilRules),
+				ratioRule(source, spec, p, *r.NilRatio, sliceNilRules),
+				ratioRule(source, spec, p, *r.NilRatio, mapNilRules),
+			)
+		}
+		if r.TrueRatio != nil {
+			options = append(options, ratioRule(source, spec, p, *r.TrueRatio, boolTrueRules))
+		}
+		g, err := g.apply(options...)
+		if err != nil {
+			return nil, err
+		}
+		g.config.Rules = append(g.config.Rules, r)
+		return g, nil
+	}
+}
+
+// String describes the Rule by its type and path.
+func (r Rule) String() string {
+	switch {
+	case r.Type == "":
+		return r.Path
+	case r.Path == "":
+		return r.Type
+	}
+	return r.Type + " at " + r.Path
+}
+
+// declaredRanges registers a range for every numeric kind able to represent it exactly.
+func declaredRanges(source string, spec specificity, p Predicate, r Range) []Option {
+	var options []Option
+	add := func(option Option, ok bool) {
+		if ok {
+			options = append(options, option)
+		}
+	}
+	add(declaredRange[int](source, spec, p, r))
+	add(declaredRange[int8](source, spec, p, r))
+	add(declaredRange[int16](source, spec, p, r))
+	add(declaredRange[int32](source, spec, p, r))
+	add(declaredRange[int64](source, spec, p, r))
+	add(declaredRange[uint](source, spec, p, r))
+	add(declaredRange[uint8](source, spec, p, r))
+	add(declaredRange[uint16](source, spec, p, r))
+	add(declaredRange[uint32](source, spec, p, r))
+	add(declaredRange[uint64](source, spec, p, r))
+	add(declaredRange[float32](source, spec, p, r))
+	add(declaredRange[float64](source, spec, p, r))
+	return options
+}
+
+func declaredRange[T numeric](source string, spec specificity, p Predicate, r Range) (Option, bool) {
+	option, err := exactRange(r, func(min, max T) Option {
+		return rangeRule(source, spec, p, min, max)
+	})
+	return option, err == nil
+}
+
+// pathMatches reports whether path matches pattern, in which '*' matches any sequence of characters.
+func pathMatches(pattern, path string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == path
+	}
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	path = path[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(path, part)
+		if i < 0 {
+			return false
+		}
+		path = path[i+len(part):]
+	}
+	return strings.HasSuffix(path, parts[len(parts)-1])
+}
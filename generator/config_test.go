@@ -0,0 +1,163 @@
+package generator_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type Shipment struct {
+	Reference string
+	Country   string
+	Weight    Celsius
+	Count     int
+	Urgent    bool
+	Note      *string
+	Items     []Address
+}
+
+const yamlConfig = `
+seed: 42
+ranges:
+  int: {min: 3, max: 3}
+  slice_length: {min: 2, max: 2}
+ratios:
+  bool_true: 1
+  pointer_nil: 0
+runes: "xyz"
+rules:
+  - path: Country
+    values: [GB]
+  - type: generator_test.Celsius
+    range: {min: -10, max: -10}
+  - path: Items[*].Number
+    range: {min: 9, max: 9}
+`
+
+func TestLoadConfig(t *testing.T) {
+	options, err := generator.LoadConfig(strings.NewReader(yamlConfig))
+	assert.Nil(t, err)
+	subject, err := generator.New().WithOptions(options...)
+	assert.Nil(t, err)
+
+	s := new(Shipment)
+	assert.Nil(t, subject.Fill(s))
+	assert.Equal(t, "GB", s.Country)
+	assert.Equal(t, Celsius(-10), s.Weight)
+	assert.Equal(t, 3, s.Count)
+	assert.True(t, s.Urgent)
+	assert.NotNil(t, s.Note)
+	assert.Len(t, s.Items, 2)
+	assert.Equal(t, 9, s.Items[1].Number)
+	assert.Equal(t, "", strings.Trim(s.Reference, "xyz"))
+
+	// exporting and reloading the configuration replays the run exactly
+	exported, err := json.Marshal(subject.Config())
+	assert.Nil(t, err)
+	path := filepath.Join(t.TempDir(), "recipe.json")
+	assert.Nil(t, os.WriteFile(path, exported, 0o600))
+
+	replay, err := generator.New().WithOptions(generator.WithConfigFile(path))
+	assert.Nil(t, err)
+	first, second := new(Shipment), new(Shipment)
+	original, _ := generator.New().WithOptions(options...)
+	assert.Nil(t, original.Fill(first))
+	assert.Nil(t, replay.Fill(second))
+	assert.Equal(t, first, second)
+}
+
+func TestConfigExport(t *testing.T) {
+	subject, err := generator.New().WithOptions(
+		generator.WithIntRange(1, 5),
+		generator.WithRange[Celsius](-1, 1),
+		generator.WithStringLengthRange(2, 3),
+		generator.WithPointerNilRatio(0.25),
+		generator.WithZeroRatio(0.5),
+		generator.WithPointerReuseRatio(0.75),
+		generator.WithNullValidRatio(0.125),
+		generator.WithDigits(3, 9),
+		generator.WithRegexpPatterns(`^a+$`, `^b+$`),
+		generator.Sparse(2),
+	)
+	assert.Nil(t, err)
+
+	c := subject.Config()
+	assert.Equal(t, generator.Range{Min: "1", Max: "5"}, c.Ranges["int"])
+	assert.Equal(t, generator.Range{Min: "2", Max: "3"}, c.Ranges["string_length"])
+	assert.Equal(t, 0.25, *c.Ratios.PointerNil)
+	assert.Equal(t, 0.5, *c.Ratios.Zero)
+	assert.Equal(t, 0.75, *c.Ratios.PointerReuse)
+	assert.Equal(t, 0.125, *c.Ratios.NullValid)
+	assert.Equal(t, generator.Range{Min: "3", Max: "9"}, c.Ranges["digits"])
+	assert.Equal(t, []string{`^a+$`, `^b+$`}, c.Regexps)
+	assert.Equal(t, 2, *c.Sparse)
+
+	// the settings are replayed from the exported configuration
+	exported, err := json.Marshal(c)
+	assert.Nil(t, err)
+	options, err := generator.LoadConfig(bytes.NewReader(exported))
+	assert.Nil(t, err)
+	replay, err := generator.New().WithOptions(options...)
+	assert.Nil(t, err)
+	assert.Equal(t, c, replay.Config())
+	assert.Equal(t, []generator.Rule{{Type: "generator_test.Celsius", Range: &generator.Range{Min: "-1", Max: "1"}}}, c.Rules)
+}
+
+func TestConfigErrors(t *testing.T) {
+	documents := map[string]string{
+		"unknown field":  `{"seeds": 1}`,
+		"unknown range":  "ranges:\n  integer: {min: 1, max: 2}\n",
+		"invalid ratio":  "ratios:\n  bool_true: 2\n",
+		"untargeted":     "rules:\n  - values: [a]\n",
+		"malformed json": `{"seed": `,
+		"inexact range":  "ranges:\n  uint8: {min: 0, max: 300}\n",
+		"fraction range": "ranges:\n  int: {min: 1.5, max: 2}\n",
+		"beyond int64":   `{"ranges": {"int64": {"min": 0, "max": 9223372036854775808}}}`,
+		"inexact length": "rules:\n  - path: Items\n    length: {min: 1, max: 2.5}\n",
+		"inexact rule":   "rules:\n  - path: Count\n    range: {min: 0, max: 1e400}\n",
+		"non-numeric":    "ranges:\n  int: {min: one, max: 2}\n",
+	}
+	for name, document := range documents {
+		document := document
+		t.Run(name, func(tt *testing.T) {
+			options, err := generator.LoadConfig(bytes.NewBufferString(document))
+			if err == nil {
+				_, err = generator.New().WithOptions(options...)
+			}
+			assert.NotNil(tt, err)
+		})
+	}
+}
+
+func TestRuleNilRatio(t *testing.T) {
+	options, err := generator.LoadConfig(strings.NewReader(`
+rules:
+  - path: Items
+    nil_ratio: 1
+  - path: Note
+    nil_ratio: 1
+  - path: Labels
+    nil_ratio: 1
+`))
+	assert.Nil(t, err)
+	subject, err := generator.New().WithOptions(options...)
+	assert.Nil(t, err)
+
+	for i := 0; i < 10; i++ {
+		var s struct {
+			Items  []Address
+			Note   *string
+			Labels map[string]string
+		}
+		assert.Nil(t, subject.Fill(&s))
+		assert.Nil(t, s.Items)
+		assert.Nil(t, s.Note)
+		assert.Nil(t, s.Labels)
+	}
+}
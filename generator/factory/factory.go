@@ -0,0 +1,183 @@
+// Package factory builds entities from reusable definitions layered on a generator, with named traits,
+// auto-incrementing sequences and associations with entities built by other factories.
+package factory
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/merlincox/reflective/generator"
+)
+
+// Factory builds values of type T.
+type Factory[T any] struct {
+	mu           sync.Mutex
+	g            generator.Generator
+	options      []generator.Option
+	sequences    []sequence
+	associations []func() ([]generator.Option, func(t *T), error)
+	count        int
+	// recorded is set when values built are recorded in the pool of the generator, to be drawn by AssociateExisting
+	recorded bool
+}
+
+type sequence struct {
+	path string
+	fn   func(n int) any
+}
+
+var registry sync.Map
+
+// Define defines and registers the factory for type T, replacing any previously defined. The options apply to every
+// value built.
+func Define[T any](options ...generator.Option) *Factory[T] {
+	f := &Factory[T]{
+		g:       generator.New(),
+		options: options,
+	}
+	registry.Store(reflect.TypeOf((*T)(nil)).Elem(), f)
+	return f
+}
+
+// Of returns the factory registered for type T.
+func Of[T any]() (*Factory[T], bool) {
+	f, ok := registry.Load(reflect.TypeOf((*T)(nil)).Elem())
+	if !ok {
+		return nil, false
+	}
+	return f.(*Factory[T]), true
+}
+
+// Build builds a value of type T with the factory registered for T.
+func Build[T any](options ...generator.Option) (T, error) {
+	f, ok := Of[T]()
+	if !ok {
+		var none T
+		return none, fmt.Errorf("factory: no factory defined for %s", reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return f.Build(options...)
+}
+
+// Using sets the generator on which the factory builds, which is otherwise a new one.
+func (f *Factory[T]) Using(g generator.Generator) *Factory[T] {
+	f.g = g
+	return f
+}
+
+// Trait defines a named trait, selected when building with generator.Trait(name).
+func (f *Factory[T]) Trait(name string, options ...generator.Option) *Factory[T] {
+	f.options = append(f.options, generator.WithTrait(name, options...))
+	return f
+}
+
+// Sequence sets the value at path to format, as by fmt.Sprintf, with the number of the value built, counting from 1.
+func (f *Factory[T]) Sequence(path, format string) *Factory[T] {
+	return f.SequenceFn(path, func(n int) any {
+		return fmt.Sprintf(format, n)
+	})
+}
+
+// SequenceFn sets the value at path to the result of fn called with the number of the value built, counting from 1.
+func (f *Factory[T]) SequenceFn(path string, fn func(n int) any) *Factory[T] {
+	f.sequences = append(f.sequences, sequence{path: path, fn: fn})
+	return f
+}
+
+// Associate builds a value of type A with af for every value of type T built with f. Unless path is empty, the
+// associated value is set at path. Link, if not nil, is then called with both values, so that it can copy keys
+// between them, as in
+//
+//	factory.Associate(orders, "", users, func(o *Order, u *User) { o.UserID = u.ID })
+func Associate[T, A any](f *Factory[T], path string, af *Factory[A], link func(t *T, a *A)) *Factory[T] {
+	return associate(f, path, link, func() (A, error) {
+		return af.Build()
+	})
+}
+
+// AssociateExisting is like Associate, but rather than building a value of type A for every value built with f, draws
+// one at random from those built with af since the association was made, which are recorded in the pool of its
+// generator, so that values built with f may share it. A value is built with af only when there is none to draw.
+func AssociateExisting[T, A any](f *Factory[T], path string, af *Factory[A], link func(t *T, a *A)) *Factory[T] {
+	af.mu.Lock()
+	af.recorded = true
+	af.mu.Unlock()
+	return associate(f, path, link, func() (A, error) {
+		if a, ok := generator.Pick[A](af.g); ok {
+			return a, nil
+		}
+		return af.Build()
+	})
+}
+
+func associate[T, A any](f *Factory[T], path string, link func(t *T, a *A), get func() (A, error)) *Factory[T] {
+	f.associations = append(f.associations, func() ([]generator.Option, func(t *T), error) {
+		a, err := get()
+		if err != nil {
+			return nil, nil, err
+		}
+		var options []generator.Option
+		if path != "" {
+			options = append(options, generator.Set(path, a))
+		}
+		return options, func(t *T) {
+			if link != nil {
+				link(t, &a)
+			}
+		}, nil
+	})
+	return f
+}
+
+// Build builds a value of type T. The options, which may select traits with generator.Trait, apply to this value
+// only, and take precedence over those of the factory.
+func (f *Factory[T]) Build(options ...generator.Option) (T, error) {
+	f.mu.Lock()
+	f.count++
+	n, recorded := f.count, f.recorded
+	f.mu.Unlock()
+
+	all := append([]generator.Option(nil), f.options...)
+	for _, s := range f.sequences {
+		all = append(all, generator.Set(s.path, s.fn(n)))
+	}
+	var links []func(t *T)
+	for _, associate := range f.associations {
+		associated, link, err := associate()
+		if err != nil {
+			var none T
+			return none, err
+		}
+		all = append(all, associated...)
+		links = append(links, link)
+	}
+	all = append(all, options...)
+
+	value, err := generator.Make[T](f.g, all...)
+	if err != nil {
+		return value, err
+	}
+	for _, link := range links {
+		link(&value)
+	}
+	if recorded {
+		f.g.Pool().Add(value)
+	}
+	return value, nil
+}
+
+// BuildN builds n values of type T.
+func (f *Factory[T]) BuildN(n int, options ...generator.Option) ([]T, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("factory: count %d is negative", n)
+	}
+	values := make([]T, n)
+	for i := range values {
+		value, err := f.Build(options...)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
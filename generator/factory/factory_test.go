@@ -0,0 +1,114 @@
+package factory_test
+
+import (
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/merlincox/reflective/generator/factory"
+	"github.com/stretchr/testify/assert"
+)
+
+type Company struct {
+	ID   int
+	Name string
+}
+
+type User struct {
+	ID        int
+	Email     string
+	Role      string
+	CompanyID int
+	Company   *Company
+}
+
+type Order struct {
+	ID     int
+	UserID int
+	Total  float64
+}
+
+func TestFactories(t *testing.T) {
+	companies := factory.Define[Company]().
+		SequenceFn("ID", func(n int) any { return 100 + n })
+	users := factory.Define[User](generator.Set("Role", "member")).
+		SequenceFn("ID", func(n int) any { return n }).
+		Sequence("Email", "user-%d@example.com").
+		Trait("admin", generator.Set("Role", "admin"))
+	factory.Associate(users, "Company", companies, func(u *User, c *Company) {
+		u.CompanyID = c.ID
+	})
+	orders := factory.Define[Order]()
+	factory.Associate(orders, "", users, func(o *Order, u *User) {
+		o.UserID = u.ID
+	})
+
+	u, err := users.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, u.ID)
+	assert.Equal(t, "user-1@example.com", u.Email)
+	assert.Equal(t, "member", u.Role)
+	assert.Equal(t, 101, u.CompanyID)
+	assert.Equal(t, 101, u.Company.ID)
+
+	admin, err := users.Build(generator.Trait("admin"))
+	assert.Nil(t, err)
+	assert.Equal(t, "admin", admin.Role)
+	assert.Equal(t, "user-2@example.com", admin.Email)
+
+	o, err := factory.Build[Order]()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, o.UserID)
+
+	all, err := orders.BuildN(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, all[0].UserID)
+	assert.Equal(t, 5, all[1].UserID)
+
+	registered, ok := factory.Of[User]()
+	assert.True(t, ok)
+	assert.Same(t, users, registered)
+}
+
+func TestFactoryErrors(t *testing.T) {
+	_, err := factory.Build[struct{ Unregistered bool }]()
+	assert.NotNil(t, err)
+
+	failing := factory.Define[Company](generator.Set("ID", "one"))
+	_, err = failing.Build()
+	assert.NotNil(t, err)
+
+	dependent := factory.Define[Order]()
+	factory.Associate(dependent, "", failing, nil)
+	_, err = dependent.Build()
+	assert.NotNil(t, err)
+
+	_, err = factory.Define[Company]().BuildN(-1)
+	assert.NotNil(t, err)
+}
+
+func TestAssociateExisting(t *testing.T) {
+	companies := factory.Define[Company]().
+		SequenceFn("ID", func(n int) any { return 200 + n })
+	users := factory.Define[User]()
+	factory.AssociateExisting(users, "Company", companies, func(u *User, c *Company) {
+		u.CompanyID = c.ID
+	})
+
+	first, err := users.Build()
+	assert.Nil(t, err)
+	second, err := users.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, 201, first.CompanyID)
+	assert.Equal(t, first.CompanyID, second.CompanyID)
+	assert.Equal(t, *first.Company, *second.Company)
+
+	_, err = companies.Build()
+	assert.Nil(t, err)
+	drawn := map[int]bool{}
+	for i := 0; i < 50; i++ {
+		u, err := users.Build()
+		assert.Nil(t, err)
+		drawn[u.CompanyID] = true
+	}
+	assert.Equal(t, map[int]bool{201: true, 202: true}, drawn)
+}
@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// FieldRule identifies a field of type F within struct type T, resolved by its offset rather than by its name.
+type FieldRule[T, F any] struct {
+	path []fieldStep
+	err  error
+}
+
+type fieldStep struct {
+	owner reflect.Type
+	name  string
+}
+
+// Field resolves the field addressed by sel, which must return a pointer to a field of its argument, for example
+//
+//	generator.Field(func(u *User) *int { return &u.Age }).Range(18, 99)
+//
+// Fields of nested struct values may be selected, but not fields reached through a pointer. Since the field is
+// named in code rather than in a string, renaming it breaks compilation instead of silently disabling the rule.
+func Field[T, F any](sel func(*T) *F) *FieldRule[T, F] {
+	rule := new(FieldRule[T, F])
+	rule.path, rule.err = resolveField(sel)
+	return rule
+}
+
+func resolveField[T, F any](sel func(*T) *F) (path []fieldStep, err error) {
+	rtype := reflect.TypeOf((*T)(nil)).Elem()
+	target := reflect.TypeOf((*F)(nil)).Elem()
+	if rtype.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Field: %s is not a struct type", rtype)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Field: selector for %s panicked: %v", rtype, r)
+		}
+	}()
+	some := new(T)
+	ptr := sel(some)
+	base := uintptr(unsafe.Pointer(some))
+	addr := uintptr(unsafe.Pointer(ptr))
+	if ptr == nil || addr < base || addr+target.Size() > base+rtype.Size() {
+		return nil, fmt.Errorf("Field: selector must return a pointer to a field of its %s argument", rtype)
+	}
+	path, ok := fieldAt(rtype, addr-base, target)
+	if !ok {
+		return nil, fmt.Errorf("Field: no field of type %s found in %s", target, rtype)
+	}
+	return path, nil
+}
+
+func fieldAt(rtype reflect.Type, offset uintptr, target reflect.Type) ([]fieldStep, bool) {
+	for i := 0; i < rtype.NumField(); i++ {
+		field := rtype.Field(i)
+		if offset < field.Offset || offset > field.Offset+field.Type.Size() {
+			continue
+		}
+		step := fieldStep{owner: rtype, name: field.Name}
+		if offset == field.Offset && field.Type == target {
+			return []fieldStep{step}, true
+		}
+		if field.Type.Kind() == reflect.Struct {
+			if rest, ok := fieldAt(field.Type, offset-field.Offset, target); ok {
+				return append([]fieldStep{step}, rest...), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// String returns the selected field in the form Type.Field, such as User.Address.Street.
+func (f *FieldRule[T, F]) String() string {
+	names := []string{reflect.TypeOf((*T)(nil)).Elem().Name()}
+	for _, step := range f.path {
+		names = append(names, step.name)
+	}
+	return strings.Join(names, ".")
+}
+
+// Predicate returns a Predicate matching values held in the field, and the lengths of such values.
+func (f *FieldRule[T, F]) Predicate() Predicate {
+	target := reflect.TypeOf((*F)(nil)).Elem()
+	return func(t *Matcher) bool {
+		if f.err != nil || t.rtype != target {
+			return false
+		}
+		c := t.parent
+		for i := len(f.path) - 1; i >= 0; i-- {
+			if c == nil || c.field == nil || c.rtype != f.path[i].owner || c.field.Name != f.path[i].name {
+				return false
+			}
+			c = c.parent
+		}
+		return true
+	}
+}
+
+// Range sets the range of a numeric field
+func (f *FieldRule[T, F]) Range(min, max F) Option {
+	return func(g *generator) (*generator, error) {
+		if f.err != nil {
+			return nil, f.err
+		}
+		p := f.Predicate()
+		source := fmt.Sprintf("Field(%s).Range", f)
+		lo, hi := reflect.ValueOf(min), reflect.ValueOf(max)
+		var o Option
+		switch lo.Kind() {
+		case reflect.Int:
+			o = rangeRule(source, fieldLevel, p, int(lo.Int()), int(hi.Int()))
+		case reflect.Int8:
+			o = rangeRule(source, fieldLevel, p, int8(lo.Int()), int8(hi.Int()))
+		case reflect.Int16:
+			o = rangeRule(source, fieldLevel, p, int16(lo.Int()), int16(hi.Int()))
+		case reflect.Int32:
+			o = rangeRule(source, fieldLevel, p, int32(lo.Int()), int32(hi.Int()))
+		case reflect.Int64:
+			o = rangeRule(source, fieldLevel, p, lo.Int(), hi.Int())
+		case reflect.Uint:
+			o = rangeRule(source, fieldLevel, p, uint(lo.Uint()), uint(hi.Uint()))
+		case reflect.Uint8:
+			o = rangeRule(source, fieldLevel, p, uint8(lo.Uint()), uint8(hi.Uint()))
+		case reflect.Uint16:
+			o = rangeRule(source, fieldLevel, p, uint16(lo.Uint()), uint16(hi.Uint()))
+		case reflect.Uint32:
+			o = rangeRule(source, fieldLevel, p, uint32(lo.Uint()), uint32(hi.Uint()))
+		case reflect.Uint64:
+			o = rangeRule(source, fieldLevel, p, lo.Uint(), hi.Uint())
+		case reflect.Float32:
+			o = rangeRule(source, fieldLevel, p, float32(lo.Float()), float32(hi.Float()))
+		case reflect.Float64:
+			o = rangeRule(source, fieldLevel, p, lo.Float(), hi.Float())
+		default:
+			return nil, fmt.Errorf("Field: Range is not supported for %s", lo.Type())
+		}
+		return o(g)
+	}
+}
+
+// Length sets the length range of a string, slice or map field
+func (f *FieldRule[T, F]) Length(min, max int) Option {
+	return func(g *generator) (*generator, error) {
+		if f.err != nil {
+			return nil, f.err
+		}
+		p := f.Predicate()
+		source := fmt.Sprintf("Field(%s).Length", f)
+		var o Option
+		switch kind := reflect.TypeOf((*F)(nil)).Elem().Kind(); kind {
+		case reflect.String:
+			o = rangeRule(source, fieldLevel, p, stringLenInt(min), stringLenInt(max))
+		case reflect.Slice:
+			o = rangeRule(source, fieldLevel, p, sliceLenInt(min), sliceLenInt(max))
+		case reflect.Map:
+			o = rangeRule(source, fieldLevel, p, mapLenInt(min), mapLenInt(max))
+		default:
+			return nil, fmt.Errorf("Field: Length is not supported for kind %s", kind)
+		}
+		return o(g)
+	}
+}
@@ -0,0 +1,60 @@
+package generator_test
+
+import (
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type User struct {
+	Name    string
+	Age     int
+	Score   int
+	Tags    []string
+	Address Address
+	Other   *Address
+}
+
+type Address struct {
+	Street string
+	Number int
+}
+
+func TestFieldRules(t *testing.T) {
+	subject := generator.New()
+	subject, err := subject.WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.Field(func(u *User) *int { return &u.Age }).Range(150, 150),
+		generator.Field(func(u *User) *string { return &u.Name }).Length(2, 2),
+		generator.Field(func(u *User) *[]string { return &u.Tags }).Length(5, 5),
+		generator.Field(func(u *User) *int { return &u.Address.Number }).Range(500, 500),
+	)
+	assert.Nil(t, err)
+
+	u := new(User)
+	assert.Nil(t, subject.Fill(u))
+	assert.Equal(t, 150, u.Age)
+	assert.Len(t, u.Name, 2)
+	assert.Len(t, u.Tags, 5)
+	assert.Equal(t, 500, u.Address.Number)
+	assert.NotEqual(t, 150, u.Score)
+	assert.NotEqual(t, 500, u.Other.Number)
+}
+
+func TestFieldErrors(t *testing.T) {
+	scenarios := map[string]generator.Option{
+		"outside argument": generator.Field(func(u *User) *int { return new(int) }).Range(1, 2),
+		"through pointer":  generator.Field(func(u *User) *int { return &u.Other.Number }).Range(1, 2),
+		"non-numeric":      generator.Field(func(u *User) *string { return &u.Name }).Range("a", "b"),
+		"non-length":       generator.Field(func(u *User) *int { return &u.Age }).Length(1, 2),
+		"invalid range":    generator.Field(func(u *User) *int { return &u.Age }).Range(2, 1),
+	}
+	for name, option := range scenarios {
+		option := option
+		t.Run(name, func(tt *testing.T) {
+			_, err := generator.New().WithOptions(option)
+			assert.NotNil(tt, err)
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package generator
 
-import "math"
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
 
 func (g *generator) chanceTrue(ratio float64) bool {
 	if ratio <= 0 {
@@ -9,55 +13,115 @@ func (g *generator) chanceTrue(ratio float64) bool {
 	if ratio >= 1 {
 		return true
 	}
-	return g.Float64() >= ratio
+	return g.Float64() < ratio
 }
 
-func (g *generator) genBool(t *Matcher) bool {
-	for _, fn := range g.boolTrueFns {
-		if out, ok := fn(t); ok {
-			return g.chanceTrue(out)
-		}
+func (g *generator) genBool(t *Matcher) (bool, error) {
+	ratio, err := g.resolveRatio(g.boolTrueRules, defBooleanTrueRatio, t)
+	return g.chanceTrue(ratio), err
+}
+
+func (g *generator) genUseNilPointer(t *Matcher) (bool, error) {
+	if t.isRequired() || g.onSetPath(t.Path()) {
+		return false, nil
 	}
-	ratio := defBooleanTrueRatio
-	if g.booleanTrueRatio != nil {
-		ratio = *g.booleanTrueRatio
+	ratio, err := g.resolveRatio(g.pointerNilRules, defNilPointerRatio, t)
+	return g.chanceTrue(ratio), err
+}
+
+// genUseNil reports whether a slice or map value should be left nil, which it never is by default.
+func (g *generator) genUseNil(rules ruleset[float64], t *Matcher) (bool, error) {
+	if t.isRequired() {
+		return false, nil
+	}
+	ratio, err := g.resolveRatio(rules, 0, t)
+	return g.chanceTrue(ratio), err
+}
+
+// genZero reports whether a struct field should be left at its zero value, which it never is by default, nor when it
+// lies on the way to a Set path.
+func (g *generator) genZero(t *Matcher) (bool, error) {
+	if t == nil || t.field == nil || t.required || g.onSetPath(t.Path()) {
+		return false, nil
 	}
-	return g.chanceTrue(ratio)
+	ratio, err := g.resolveRatio(g.zeroRules, 0, t)
+	return g.chanceTrue(ratio), err
 }
 
-func (g *generator) genUseNilPointer(t *Matcher) bool {
-	for _, fn := range g.pointerNilFns {
-		if out, ok := fn(t); ok {
-			return g.chanceTrue(out)
+// sparseFields chooses which settable fields of the root struct to populate when Sparse applies, returning nil when
+// all are to be.
+func (g *generator) sparseFields(value reflect.Value, t *Matcher) []bool {
+	if t != nil || g.sparse == 0 {
+		return nil
+	}
+	var settable []int
+	for i := 0; i < value.NumField(); i++ {
+		if g.settableField(value, i).CanSet() {
+			settable = append(settable, i)
 		}
 	}
-	ratio := defNilPointerRatio
-	if g.pointerNilRatio != nil {
-		ratio = *g.pointerNilRatio
+	populated := make([]bool, value.NumField())
+	for n := 0; n < g.sparse && n < len(settable); n++ {
+		j := n + int(g.InclusiveUint64n(0, uint64(len(settable)-1-n)))
+		settable[n], settable[j] = settable[j], settable[n]
+		populated[settable[n]] = true
 	}
-	return g.chanceTrue(ratio)
+	return populated
 }
 
-func (g *generator) genString(t *Matcher) string {
-	for _, fn := range g.stringFns {
-		if out, ok := fn(t); ok {
-			return out
-		}
+// reusePointer sets value to a pointer already allocated in the current fill, if the reuse ratio allows, reporting
+// whether it did so.
+func (g *generator) reusePointer(value reflect.Value, t *Matcher) (bool, error) {
+	candidates := g.graph[value.Type()]
+	if len(candidates) == 0 {
+		return false, nil
 	}
-	stringLen := g.genStringLen(t)
-	if stringLen == 0 {
-		return ""
+	ratio, err := g.resolveRatio(g.pointerReuse, 0, t)
+	if err != nil || !g.chanceTrue(ratio) {
+		return false, err
 	}
-	source := getDefRunes()
-	if len(g.runes) != 0 {
-		source = g.runes
+	value.Set(candidates[g.InclusiveUint64n(0, uint64(len(candidates)-1))])
+	return true, nil
+}
+
+func (g *generator) resolveRatio(rules ruleset[float64], def float64, t *Matcher) (float64, error) {
+	ratio, source, ok, err := rules.find(g, t, validateRatio)
+	if err != nil {
+		return 0, err
 	}
-	for _, fn := range g.runesFns {
-		if out, ok := fn(t); ok {
-			source = out
-		}
+	if !ok {
+		ratio, source = def, defaultSource
 	}
-	return g.fillString(stringLen, source)
+	return ratio, g.trace(t, source)
+}
+
+func (g *generator) genString(t *Matcher) (string, error) {
+	out, source, ok, err := g.stringRules.find(g, t, nil)
+	if err != nil {
+		return out, err
+	}
+	if ok {
+		return out, g.trace(t, source)
+	}
+	length, lengthSource, err := resolveRange[stringLenInt](g, t)
+	if err != nil {
+		return "", err
+	}
+	runes, runesSource, ok, err := g.runesRules.find(g, t, validateRunes)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		runes, runesSource = getDefRunes(), defaultSource
+	}
+	if err := g.trace(t, fmt.Sprintf("length: %s, runes: %s", lengthSource, runesSource)); err != nil {
+		return "", err
+	}
+	stringLen := int(randomIn(g, length))
+	if stringLen == 0 {
+		return "", nil
+	}
+	return g.fillString(stringLen, runes), nil
 }
 
 func (g *generator) fillString(size int, source []rune) string {
@@ -74,17 +138,26 @@ func (g *generator) fillString(size int, source []rune) string {
 	return string(runes)
 }
 
-func genNumeric[T numeric](set nset[T], t *Matcher, g *generator) T {
-	mm := defaultInterval[T]()
-	if set.interval != nil {
-		mm = *set.interval
+func resolveRange[T numeric](g *generator, t *Matcher) (interval[T], string, error) {
+	mm, source, ok, err := rangesOf[T](g).find(g, t, validateInterval[T])
+	if err != nil {
+		return mm, source, err
 	}
-	for _, fn := range set.fns {
-		if min, max, ok := fn(t); ok {
-			mm.min = min
-			mm.max = max
-		}
+	if !ok {
+		return defaultInterval[T](), defaultSource, nil
+	}
+	return mm, source, nil
+}
+
+func genNumeric[T numeric](g *generator, t *Matcher) (T, error) {
+	mm, source, err := resolveRange[T](g, t)
+	if err != nil {
+		return 0, err
 	}
+	return randomIn(g, mm), g.trace(t, source)
+}
+
+func randomIn[T numeric](g *generator, mm interval[T]) T {
 	if mm.min == mm.max {
 		return mm.min
 	}
@@ -92,6 +165,10 @@ func genNumeric[T numeric](set nset[T], t *Matcher, g *generator) T {
 	switch any(mm.min).(type) {
 	case int, int64:
 		return T(g.InclusiveInt64n(int64(mm.min), int64(mm.max)))
+	case uint, uint64:
+		return T(g.InclusiveUint64n(uint64(mm.min), uint64(mm.max)))
+	case uint32:
+		return T(g.InclusiveUint32n(uint32(mm.min), uint32(mm.max)))
 	case float32:
 		return ((T(g.Float32()) * ((mm.max / divisor) - (mm.min / divisor))) + (mm.min / divisor)) * divisor
 	case float64:
@@ -100,62 +177,12 @@ func genNumeric[T numeric](set nset[T], t *Matcher, g *generator) T {
 	return T(g.InclusiveInt32n(int32(mm.min), int32(mm.max)))
 }
 
-func (g *generator) genFloat32(t *Matcher) float32 {
-	return genNumeric(g.float32Set, t, g)
-}
-
-func (g *generator) genFloat64(t *Matcher) float64 {
-	return genNumeric(g.float64Set, t, g)
-}
-
-func (g *generator) genStringLen(t *Matcher) int {
-	return int(genNumeric(g.stringLenSet, t, g))
-}
-
-func (g *generator) genSliceLen(t *Matcher) int {
-	return int(genNumeric(g.sliceLenSet, t, g))
-}
-
-func (g *generator) genMapLen(t *Matcher) int {
-	return int(genNumeric(g.mapLenSet, t, g))
-}
-
-func (g *generator) genInt(t *Matcher) int {
-	return genNumeric(g.intSet, t, g)
-}
-
-func (g *generator) genInt8(t *Matcher) int8 {
-	return genNumeric(g.int8Set, t, g)
-}
-
-func (g *generator) genInt16(t *Matcher) int16 {
-	return genNumeric(g.int16Set, t, g)
-}
-
-func (g *generator) genInt32(t *Matcher) int32 {
-	return genNumeric(g.int32Set, t, g)
-}
-
-func (g *generator) genInt64(t *Matcher) int64 {
-	return genNumeric(g.int64Set, t, g)
-}
-
-func (g *generator) genUint(t *Matcher) uint {
-	return genNumeric(g.uintSet, t, g)
-}
-
-func (g *generator) genUint8(t *Matcher) uint8 {
-	return genNumeric(g.uint8Set, t, g)
-}
-
-func (g *generator) genUint16(t *Matcher) uint16 {
-	return genNumeric(g.uint16Set, t, g)
-}
-
-func (g *generator) genUint32(t *Matcher) uint32 {
-	return genNumeric(g.uint32Set, t, g)
+func (g *generator) genSliceLen(t *Matcher) (int, error) {
+	size, err := genNumeric[sliceLenInt](g, t)
+	return int(size), err
 }
 
-func (g *generator) genUint64(t *Matcher) uint64 {
-	return genNumeric(g.uint64Set, t, g)
+func (g *generator) genMapLen(t *Matcher) (int, error) {
+	size, err := genNumeric[mapLenInt](g, t)
+	return int(size), err
 }
@@ -0,0 +1,23 @@
+package generator_test
+
+import (
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatioDirection(t *testing.T) {
+	g, err := generator.New().WithOptions(generator.WithPointerNilRatio(0.1))
+	assert.Nil(t, err)
+
+	nils := 0
+	for i := 0; i < 1000; i++ {
+		var p *int
+		assert.Nil(t, g.Fill(&p))
+		if p == nil {
+			nils++
+		}
+	}
+	assert.Less(t, nils, 300)
+}
@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Generatable is implemented by types which generate their own values, keeping generation logic next to types with
+// invariants. Fill calls Generate, with the Matcher of the value, instead of filling the value itself, as
+// encoding/json calls UnmarshalJSON. Rules set by Set still take precedence.
+type Generatable interface {
+	Generate(r Randomiser, m *Matcher) error
+}
+
+var generatableType = reflect.TypeOf((*Generatable)(nil)).Elem()
+
+// generate fills value by its Generate method if its type implements Generatable through a pointer, reporting whether
+// it did so.
+func (g *generator) generate(value reflect.Value, matcher *Matcher) (bool, error) {
+	rtype := value.Type()
+	if rtype.Kind() == reflect.Pointer || !value.CanAddr() || !reflect.PointerTo(rtype).Implements(generatableType) {
+		return false, nil
+	}
+	t := matcher.forSimpleType(rtype)
+	if err := recovered(func() error { return value.Addr().Interface().(Generatable).Generate(g, t) }); err != nil {
+		return true, fmt.Errorf("%s.Generate at %q: %w", rtype, t.Path(), err)
+	}
+	return true, nil
+}
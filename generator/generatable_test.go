@@ -0,0 +1,84 @@
+package generator_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type Cents struct {
+	Amount   int64
+	Currency string
+}
+
+func (c *Cents) Generate(r generator.Randomiser, m *generator.Matcher) error {
+	if m.FieldName() == "Refund" {
+		return errors.New("refunds are not generated")
+	}
+	c.Amount = int64(r.Uint32n(10000)) * 5
+	c.Currency = "GBP"
+	return nil
+}
+
+type SortedSet []int
+
+func (s *SortedSet) Generate(r generator.Randomiser, _ *generator.Matcher) error {
+	*s = make(SortedSet, r.Uint32n(10))
+	for i := range *s {
+		(*s)[i] = int(r.Uint32n(1000))
+	}
+	sort.Ints(*s)
+	return nil
+}
+
+type Invoice struct {
+	Total   Cents
+	Fees    []*Cents
+	Numbers SortedSet
+}
+
+type Refunded struct {
+	Refund Cents
+}
+
+func TestGeneratable(t *testing.T) {
+	g := generator.New()
+
+	var invoice Invoice
+	assert.Nil(t, g.Fill(&invoice))
+	assert.Equal(t, "GBP", invoice.Total.Currency)
+	assert.Zero(t, invoice.Total.Amount%5)
+	for _, fee := range invoice.Fees {
+		if fee != nil {
+			assert.Equal(t, "GBP", fee.Currency)
+		}
+	}
+	assert.True(t, sort.IntsAreSorted(invoice.Numbers))
+
+	var c Cents
+	assert.Nil(t, g.Fill(&c))
+	assert.Equal(t, "GBP", c.Currency)
+
+	err := g.Fill(&Refunded{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `generator_test.Cents.Generate at "Refund": refunds are not generated`)
+}
+
+type Checked string
+
+func (c *Checked) Generate(_ generator.Randomiser, m *generator.Matcher) error {
+	*c = Checked(m.Parent().Path()[:100])
+	return nil
+}
+
+func TestGeneratablePanics(t *testing.T) {
+	var value struct {
+		Code Checked
+	}
+	err := generator.New().Fill(&value)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `generator_test.Checked.Generate at "Code": panic: runtime error: slice bounds out of range`)
+}
@@ -17,6 +17,9 @@ const (
 	defBooleanTrueRatio = 0.5
 	defMaxInt           = int(math.MaxInt8)
 	defMaxFloat         = float64(math.MaxInt8)
+	defMinDigits        = 1
+	defMaxDigits        = 20
+	defNullValidRatio   = 0.5
 )
 
 func getDefRunes() []rune {
@@ -26,42 +29,74 @@ func getDefRunes() []rune {
 type stringLenInt int
 type mapLenInt int
 type sliceLenInt int
+type digitsInt int
 
 type numeric interface {
-	int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | float32 | float64 | stringLenInt | mapLenInt | sliceLenInt
+	int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | float32 | float64 | stringLenInt | mapLenInt | sliceLenInt | digitsInt
 }
 
 type generator struct {
 	rand Randomiser
 
-	runes            []rune
-	stringFns        []func(t *Matcher) (string, bool)
-	booleanTrueRatio *float64
-	boolTrueFns      []func(t *Matcher) (float64, bool)
-	pointerNilRatio  *float64
-	pointerNilFns    []func(t *Matcher) (float64, bool)
-	runesFns         []func(t *Matcher) ([]rune, bool)
-
-	stringLenSet nset[stringLenInt]
-	mapLenSet    nset[mapLenInt]
-	sliceLenSet  nset[sliceLenInt]
-	float32Set   nset[float32]
-	float64Set   nset[float64]
-	intSet       nset[int]
-	int8Set      nset[int8]
-	int16Set     nset[int16]
-	int32Set     nset[int32]
-	int64Set     nset[int64]
-	uintSet      nset[uint]
-	uint8Set     nset[uint8]
-	uint16Set    nset[uint16]
-	uint32Set    nset[uint32]
-	uint64Set    nset[uint64]
-}
-
-type nset[T numeric] struct {
-	interval *interval[T]
-	fns      []func(t *Matcher) (T, T, bool)
+	seq      int
+	priority int
+	tracer   func(t *Matcher, source string)
+	config   Config
+	traits   map[string][]Option
+
+	afterFills  map[reflect.Type][]func(p any, r Randomiser) error
+	fieldOrders map[reflect.Type][]int
+	oneOfs      map[reflect.Type][]oneOfGroup
+	setters     map[reflect.Type]bool
+
+	pool        *Pool
+	entityTypes map[reflect.Type]bool
+
+	validators  map[reflect.Type][]func(v any) error
+	filters     []func(t *Matcher, v any) bool
+	maxAttempts int
+	stats       *checkStats
+
+	// graph holds the pointers allocated during a Fill, by type, when pointers may be reused
+	graph map[reflect.Type][]reflect.Value
+
+	overrides ruleset[reflect.Value]
+	samples   ruleset[reflect.Value]
+	// setPaths holds the path patterns of Set rules, so that pointers on the way to them are not left nil
+	setPaths []string
+
+	stringRules     ruleset[string]
+	runesRules      ruleset[[]rune]
+	boolTrueRules   ruleset[float64]
+	pointerNilRules ruleset[float64]
+	pointerReuse    ruleset[float64]
+	sliceNilRules   ruleset[float64]
+	mapNilRules     ruleset[float64]
+	zeroRules       ruleset[float64]
+	nullValidRules  ruleset[float64]
+	regexps         []string
+	sparse          int
+
+	// unexported enables filling unexported fields, of the packages matching unexportedPkgs if there are any
+	unexported     bool
+	unexportedPkgs []string
+
+	stringLenRanges ruleset[interval[stringLenInt]]
+	mapLenRanges    ruleset[interval[mapLenInt]]
+	sliceLenRanges  ruleset[interval[sliceLenInt]]
+	digitsRanges    ruleset[interval[digitsInt]]
+	float32Ranges   ruleset[interval[float32]]
+	float64Ranges   ruleset[interval[float64]]
+	intRanges       ruleset[interval[int]]
+	int8Ranges      ruleset[interval[int8]]
+	int16Ranges     ruleset[interval[int16]]
+	int32Ranges     ruleset[interval[int32]]
+	int64Ranges     ruleset[interval[int64]]
+	uintRanges      ruleset[interval[uint]]
+	uint8Ranges     ruleset[interval[uint8]]
+	uint16Ranges    ruleset[interval[uint16]]
+	uint32Ranges    ruleset[interval[uint32]]
+	uint64Ranges    ruleset[interval[uint64]]
 }
 
 type interval[T numeric] struct {
@@ -69,16 +104,48 @@ type interval[T numeric] struct {
 	max T
 }
 
+// Generator fills data structures pseudo-randomly according to its options. New returns the standard implementation;
+// other implementations, such as mocks or decorators, may be substituted wherever a Generator is accepted.
+type Generator interface {
+	Randomiser
+	Inclusive
+
+	// Fill fills a data structure. Its argument must be a pointer to the structure. Any options apply to this call
+	// only.
+	Fill(a any, options ...Option) error
+	// WithOptions adds options to the Generator, returning the customised Generator.
+	WithOptions(options ...Option) (Generator, error)
+	// Clone returns a copy of the Generator, which may be customised without affecting the original.
+	Clone() Generator
+	// Derive returns a copy of the Generator customised with options, leaving the original unchanged.
+	Derive(options ...Option) (Generator, error)
+	// Config returns the declarative settings of the Generator.
+	Config() Config
+	// Pool returns the pool of entities recorded by the Generator.
+	Pool() *Pool
+}
+
+var _ Generator = (*generator)(nil)
+
 // Option defines an option for customising the generator behaviour
 type Option func(*generator) (*generator, error)
 
 // New creates a new generator
-func New() *generator {
-	return new(generator)
+func New() Generator {
+	return &generator{pool: newPool()}
 }
 
-// WithOptions adds options to a generator, returning the customised generator
-func (g *generator) WithOptions(options ...Option) (*generator, error) {
+// WithOptions adds options to a generator, returning the customised generator. The generator is modified in place;
+// use Derive to customise a copy instead.
+func (g *generator) WithOptions(options ...Option) (Generator, error) {
+	g, err := g.apply(options...)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (g *generator) apply(options ...Option) (*generator, error) {
 	var err error
 	for _, o := range options {
 		g, err = o(g)
@@ -90,130 +157,276 @@ func (g *generator) WithOptions(options ...Option) (*generator, error) {
 	return g, nil
 }
 
+// Clone returns a copy of the generator, to which options may be added without affecting the original. The copy shares
+// the randomiser of the original.
+func (g *generator) Clone() Generator {
+	return g.clone()
+}
+
+func (g *generator) clone() *generator {
+	clone := *g
+	clone.config = g.config.clone()
+	return &clone
+}
+
+// Derive returns a copy of the generator customised with options, leaving the original unchanged.
+func (g *generator) Derive(options ...Option) (Generator, error) {
+	return g.clone().WithOptions(options...)
+}
+
 // Fill fills a data structure, by default pseudo-randomly. Its argument must be a pointer to the structure.
-func (g *generator) Fill(a any) error {
+// An error returned or a panic raised by a callback stops the fill, and is returned annotated with the path of the
+// value being generated. Any options apply to this call only.
+func (g *generator) Fill(a any, options ...Option) error {
+	if len(options) != 0 {
+		derived, err := g.clone().apply(options...)
+		if err != nil {
+			return err
+		}
+		return derived.Fill(a)
+	}
 
 	value := reflect.ValueOf(a)
 	if value.Kind() != reflect.Pointer {
 		return fmt.Errorf("the argument to Fill to must be a pointer")
 	}
 
-	g.fill(value.Elem(), nil)
-	return nil
+	if len(g.pointerReuse) != 0 || g.checks() {
+		filler := *g
+		if len(g.pointerReuse) != 0 {
+			filler.graph = map[reflect.Type][]reflect.Value{value.Type(): {value}}
+		}
+		if g.checks() {
+			filler.stats = &checkStats{}
+		}
+		return filler.fill(value.Elem(), nil)
+	}
+
+	return g.fill(value.Elem(), nil)
 }
 
-func (g *generator) fill(value reflect.Value, matcher *Matcher) {
+func (g *generator) fill(value reflect.Value, matcher *Matcher) error {
 	if !value.CanSet() {
-		return
+		return nil
 	}
+	if set, err := g.setOverride(value, matcher); set || err != nil {
+		return err
+	}
+	if zero, err := g.genZero(matcher); zero || err != nil {
+		value.Set(reflect.Zero(value.Type()))
+		return err
+	}
+	if err := g.fillChecked(value, matcher); err != nil {
+		return err
+	}
+	g.record(value)
+	return nil
+}
+
+// fillValue generates a value, which is not set by a rule or left zero.
+func (g *generator) fillValue(value reflect.Value, matcher *Matcher) error {
 	rtype := value.Type()
+	if sampled, err := g.fillBySample(value, matcher); sampled || err != nil {
+		return err
+	}
+	if generated, err := g.generate(value, matcher); generated || err != nil {
+		return err
+	}
+	if build, ok := builtinFor(rtype); ok {
+		return build(g, value, matcher)
+	}
+	if set, err := g.fillBySetter(value, matcher); set || err != nil {
+		return err
+	}
 
 	switch value.Kind() {
 	case reflect.Pointer:
-		if g.genUseNilPointer(matcher.forSimpleType(rtype)) {
-			return
+		useNil, err := g.genUseNilPointer(matcher.forSimpleType(rtype))
+		if err != nil || useNil {
+			return err
+		}
+		if reused, err := g.reusePointer(value, matcher.forSimpleType(rtype)); reused || err != nil {
+			return err
 		}
 		value.Set(reflect.New(value.Type().Elem()))
-		g.fill(value.Elem(), matcher.forSimpleType(rtype))
+		if g.graph != nil {
+			g.graph[rtype] = append(g.graph[rtype], value)
+			g.onReject(func() {
+				g.graph[rtype] = g.graph[rtype][:len(g.graph[rtype])-1]
+			})
+		}
+		return g.fill(value.Elem(), matcher.forSimpleType(rtype))
 
 	case reflect.Bool:
-		randBool := g.genBool(matcher.forSimpleType(rtype))
+		randBool, err := g.genBool(matcher.forSimpleType(rtype))
+		if err != nil {
+			return err
+		}
 		value.SetBool(randBool)
 
 	case reflect.Int:
-		randInt := int64(g.genInt(matcher.forSimpleType(rtype)))
-		value.SetInt(randInt)
+		return setInt[int](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Int8:
-		randInt := int64(g.genInt8(matcher.forSimpleType(rtype)))
-		value.SetInt(randInt)
+		return setInt[int8](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Int16:
-		randInt := int64(g.genInt16(matcher.forSimpleType(rtype)))
-		value.SetInt(randInt)
+		return setInt[int16](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Int32:
-		randInt := int64(g.genInt32(matcher.forSimpleType(rtype)))
-		value.SetInt(randInt)
+		return setInt[int32](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Int64:
-		randInt := g.genInt64(matcher.forSimpleType(rtype))
-		value.SetInt(randInt)
+		return setInt[int64](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Uint:
-		randUint := uint64(g.genUint(matcher.forSimpleType(rtype)))
-		value.SetUint(randUint)
+		return setUint[uint](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Uint8:
-		randUint := uint64(g.genUint8(matcher.forSimpleType(rtype)))
-		value.SetUint(randUint)
+		return setUint[uint8](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Uint16:
-		randUint := uint64(g.genUint16(matcher.forSimpleType(rtype)))
-		value.SetUint(randUint)
+		return setUint[uint16](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Uint32:
-		randUint := uint64(g.genUint32(matcher.forSimpleType(rtype)))
-		value.SetUint(randUint)
+		return setUint[uint32](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Uint64:
-		randUint := g.genUint64(matcher.forSimpleType(rtype))
-		value.SetUint(randUint)
+		return setUint[uint64](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Float32:
-		randFloat := float64(g.genFloat32(matcher.forSimpleType(rtype)))
-		value.SetFloat(randFloat)
+		return setFloat[float32](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Float64:
-		randFloat := g.genFloat64(matcher.forSimpleType(rtype))
-		value.SetFloat(randFloat)
+		return setFloat[float64](g, value, matcher.forSimpleType(rtype))
 
 	case reflect.Complex64:
-		r := g.genFloat32(matcher.forRealPart(rtype))
-		i := g.genFloat32(matcher.forImaginaryPart(rtype))
-		value.SetComplex(complex128(complex(r, i)))
+		return setComplex[float32](g, value, matcher, rtype)
 
 	case reflect.Complex128:
-		r := g.genFloat64(matcher.forRealPart(rtype))
-		i := g.genFloat64(matcher.forImaginaryPart(rtype))
-		value.SetComplex(complex(r, i))
+		return setComplex[float64](g, value, matcher, rtype)
 
 	case reflect.String:
-		randStringVal := g.genString(matcher.forSimpleType(rtype))
+		randStringVal, err := g.genString(matcher.forSimpleType(rtype))
+		if err != nil {
+			return err
+		}
 		value.SetString(randStringVal)
 
 	case reflect.Slice:
+		useNil, err := g.genUseNil(g.sliceNilRules, matcher.forSimpleType(rtype))
+		if err != nil || useNil {
+			return err
+		}
 		elementType := rtype.Elem()
-		size := g.genSliceLen(matcher.forSliceLen(rtype))
+		size, err := g.genSliceLen(matcher.forSliceLen(rtype))
+		if err != nil {
+			return err
+		}
 		sliceVal := reflect.MakeSlice(reflect.SliceOf(elementType), 0, size)
 		for i := 0; i < size; i++ {
 			newElement := reflect.Indirect(reflect.New(elementType))
-			g.fill(newElement, matcher.forSliceElement(rtype, i, size))
+			if err := g.fill(newElement, matcher.forSliceElement(rtype, i, size)); err != nil {
+				return err
+			}
 			sliceVal = reflect.Append(sliceVal, newElement)
 		}
 		value.Set(sliceVal)
 
 	case reflect.Array:
 		for i := 0; i < value.Len(); i++ {
-			g.fill(value.Index(i), matcher.forArrayElement(rtype, i, value.Len()))
+			if err := g.fill(value.Index(i), matcher.forArrayElement(rtype, i, value.Len())); err != nil {
+				return err
+			}
 		}
 
 	case reflect.Map:
+		useNil, err := g.genUseNil(g.mapNilRules, matcher.forSimpleType(rtype))
+		if err != nil || useNil {
+			return err
+		}
 		mapVal := reflect.MakeMap(rtype)
-		size := g.genMapLen(matcher.forMapLen(rtype))
+		size, err := g.genMapLen(matcher.forMapLen(rtype))
+		if err != nil {
+			return err
+		}
 		// note that actual map length will be lower than size if any duplicate keys are generated
 		for i := 0; i < size; i++ {
 			newKey := reflect.Indirect(reflect.New(rtype.Key()))
-			g.fill(newKey, matcher.forMapKey(rtype))
+			if err := g.fill(newKey, matcher.forMapKey(rtype)); err != nil {
+				return err
+			}
 			newElement := reflect.Indirect(reflect.New(rtype.Elem()))
-			g.fill(newElement, matcher.forMapElement(rtype, newKey.Interface()))
+			if err := g.fill(newElement, matcher.forMapElement(rtype, newKey.Interface())); err != nil {
+				return err
+			}
 			mapVal.SetMapIndex(newKey, newElement)
 		}
 		value.Set(mapVal)
 
 	case reflect.Struct:
-		for i := 0; i < value.NumField(); i++ {
-			g.fill(value.Field(i), matcher.forField(rtype, rtype.Field(i)))
+		populated := g.sparseFields(value, matcher)
+		order, err := g.fieldOrder(rtype)
+		if err != nil {
+			return err
+		}
+		chosen, excluded, err := g.chooseOneOfs(rtype)
+		if err != nil {
+			return err
+		}
+		for _, i := range order {
+			field := matcher.forField(value, i)
+			if excluded[i] || populated != nil && !populated[i] && !g.setsPath(field.Path()) {
+				if field := g.settableField(value, i); field.CanSet() {
+					field.Set(reflect.Zero(field.Type()))
+				}
+				continue
+			}
+			field.required = chosen[i]
+			if err := g.fill(g.settableField(value, i), field); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
+}
+
+func setInt[T int | int8 | int16 | int32 | int64](g *generator, value reflect.Value, matcher *Matcher) error {
+	randInt, err := genNumeric[T](g, matcher)
+	if err != nil {
+		return err
+	}
+	value.SetInt(int64(randInt))
+	return nil
+}
+
+func setUint[T uint | uint8 | uint16 | uint32 | uint64](g *generator, value reflect.Value, matcher *Matcher) error {
+	randUint, err := genNumeric[T](g, matcher)
+	if err != nil {
+		return err
+	}
+	value.SetUint(uint64(randUint))
+	return nil
+}
+
+func setFloat[T float32 | float64](g *generator, value reflect.Value, matcher *Matcher) error {
+	randFloat, err := genNumeric[T](g, matcher)
+	if err != nil {
+		return err
+	}
+	value.SetFloat(float64(randFloat))
+	return nil
+}
+
+func setComplex[T float32 | float64](g *generator, value reflect.Value, matcher *Matcher, rtype reflect.Type) error {
+	r, err := genNumeric[T](g, matcher.forRealPart(rtype))
+	if err != nil {
+		return err
+	}
+	i, err := genNumeric[T](g, matcher.forImaginaryPart(rtype))
+	if err != nil {
+		return err
+	}
+	value.SetComplex(complex(float64(r), float64(i)))
+	return nil
 }
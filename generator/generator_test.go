@@ -0,0 +1,44 @@
+package generator_test
+
+import (
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingGenerator struct {
+	generator.Generator
+	fills int
+}
+
+func (c *countingGenerator) Fill(a any, options ...generator.Option) error {
+	c.fills++
+	return c.Generator.Fill(a, options...)
+}
+
+type harness struct {
+	gen generator.Generator
+}
+
+func TestGeneratorDecorator(t *testing.T) {
+	counter := &countingGenerator{Generator: generator.New()}
+	h := harness{gen: counter}
+
+	var first, second User
+	assert.Nil(t, h.gen.Fill(&first))
+	assert.Nil(t, h.gen.Fill(&second))
+	assert.Equal(t, 2, counter.fills)
+
+	derived, err := h.gen.Derive(generator.WithIntRange(1, 1))
+	assert.Nil(t, err)
+	var n int
+	assert.Nil(t, derived.Fill(&n))
+	assert.Equal(t, 1, n)
+}
+
+func TestWithOptionsError(t *testing.T) {
+	subject, err := generator.New().WithOptions(generator.WithIntRange(2, 1))
+	assert.NotNil(t, err)
+	assert.Nil(t, subject)
+}
@@ -0,0 +1,46 @@
+package generator_test
+
+import (
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type Node struct {
+	Value int
+	Next  *Node
+	Other *Node
+}
+
+func TestPointerReuse(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.WithPointerReuseRatio(1),
+	)
+	assert.Nil(t, err)
+
+	node := &Node{}
+	assert.Nil(t, g.Fill(node))
+	assert.Same(t, node, node.Next)
+	assert.Same(t, node, node.Other)
+
+	g, err = generator.New().WithOptions(generator.WithPointerReuseRatio(0.5))
+	assert.Nil(t, err)
+
+	cycles := 0
+	for i := 0; i < 100; i++ {
+		node = &Node{}
+		assert.Nil(t, g.Fill(node))
+		seen := map[*Node]bool{}
+		for n := node; n != nil; n = n.Next {
+			if seen[n] {
+				cycles++
+				break
+			}
+			seen[n] = true
+		}
+	}
+	assert.Greater(t, cycles, 0)
+	assert.Less(t, cycles, 100)
+}
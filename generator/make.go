@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+)
+
+// Make returns a value of type T filled by g. Any options apply to this call only.
+func Make[T any](g Generator, options ...Option) (T, error) {
+	var value T
+	err := g.Fill(&value, options...)
+	return value, err
+}
+
+// MakeN returns n values of type T filled by g. Any options apply to this call only.
+func MakeN[T any](g Generator, n int, options ...Option) ([]T, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("MakeN: count %d is negative", n)
+	}
+	g, err := deriveFor(g, options)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]T, n)
+	for i := range values {
+		if err := g.Fill(&values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func deriveFor(g Generator, options []Option) (Generator, error) {
+	if len(options) == 0 {
+		return g, nil
+	}
+	return g.Derive(options...)
+}
+
+// Iterator produces an unbounded sequence of values of type T.
+type Iterator[T any] struct {
+	g Generator
+}
+
+// NewIterator returns an Iterator of values filled by g. Any options apply to the Iterator only.
+func NewIterator[T any](g Generator, options ...Option) (*Iterator[T], error) {
+	g, err := deriveFor(g, options)
+	if err != nil {
+		return nil, err
+	}
+	return &Iterator[T]{g: g}, nil
+}
+
+// Next returns the next value in the sequence.
+func (it *Iterator[T]) Next() (T, error) {
+	return Make[T](it.g)
+}
+
+// Stream sends values of type T filled by g on the first channel returned until ctx is done or an error occurs, in
+// which case the error is sent on the second channel. Both channels are then closed. Any options apply to the stream
+// only. Values are filled on a separate goroutine, so g must not be used concurrently if its randomiser is not safe
+// for concurrent use, as is the case for one set with WithSeed.
+func Stream[T any](ctx context.Context, g Generator, options ...Option) (<-chan T, <-chan error) {
+	values := make(chan T)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(values)
+		it, err := NewIterator[T](g, options...)
+		if err != nil {
+			errs <- err
+			return
+		}
+		for {
+			value, err := it.Next()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case values <- value:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return values, errs
+}
@@ -0,0 +1,62 @@
+package generator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	g := generator.New()
+
+	u, err := generator.Make[User](g, generator.WithPointerNilRatio(0))
+	assert.Nil(t, err)
+	assert.NotNil(t, u.Other)
+
+	n, err := generator.Make[int](g, generator.WithIntRange(4, 4))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, n)
+
+	users, err := generator.MakeN[User](g, 3, generator.WithStringLengthRange(1, 1))
+	assert.Nil(t, err)
+	assert.Len(t, users, 3)
+	for _, u := range users {
+		assert.Len(t, u.Name, 1)
+	}
+
+	_, err = generator.Make[int](g, generator.WithIntRange(4, 3))
+	assert.NotNil(t, err)
+	_, err = generator.MakeN[int](g, 2, generator.WithIntRange(4, 3))
+	assert.NotNil(t, err)
+	_, err = generator.MakeN[int](g, -1)
+	assert.NotNil(t, err)
+}
+
+func TestIterator(t *testing.T) {
+	it, err := generator.NewIterator[int](generator.New(), generator.WithIntRange(1, 3))
+	assert.Nil(t, err)
+	for i := 0; i < 10; i++ {
+		n, err := it.Next()
+		assert.Nil(t, err)
+		assert.True(t, n >= 1 && n <= 3)
+	}
+}
+
+func TestStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	values, errs := generator.Stream[string](ctx, generator.New(), generator.WithStringLengthRange(2, 2))
+	for i := 0; i < 5; i++ {
+		assert.Len(t, <-values, 2)
+	}
+	cancel()
+	for range values {
+	}
+	assert.Nil(t, <-errs)
+
+	ints, errs := generator.Stream[int](context.Background(), generator.New(), generator.WithIntRange(4, 3))
+	assert.NotNil(t, <-errs)
+	_, open := <-ints
+	assert.False(t, open)
+}
@@ -0,0 +1,126 @@
+// Package match provides composable predicates for selecting the contexts in which generator rules apply.
+package match
+
+import (
+	"path"
+	"reflect"
+
+	"github.com/merlincox/reflective/generator"
+)
+
+// Any matches every context.
+func Any() generator.Predicate {
+	return func(t *generator.Matcher) bool {
+		return true
+	}
+}
+
+// Type matches values of type T, or of a pointer to T.
+func Type[T any]() generator.Predicate {
+	rtype := reflect.TypeOf((*T)(nil)).Elem()
+	return func(t *generator.Matcher) bool {
+		return t.MatchesType(rtype)
+	}
+}
+
+// Kind matches values of any of the given kinds.
+func Kind(kinds ...reflect.Kind) generator.Predicate {
+	return func(t *generator.Matcher) bool {
+		for _, kind := range kinds {
+			if t.Type().Kind() == kind {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FieldOf matches values held in any of the named fields of struct type T.
+func FieldOf[T any](names ...string) generator.Predicate {
+	var some T
+	return func(t *generator.Matcher) bool {
+		return t.MatchesAFieldOf(some, names...)
+	}
+}
+
+// Tag matches values held in a struct field whose tag has the given key with a value matching pattern.
+// The pattern "*" matches any value, including the empty value; other patterns use the syntax of path.Match.
+func Tag(key, pattern string) generator.Predicate {
+	return func(t *generator.Matcher) bool {
+		value, ok := t.Tag(key)
+		if !ok {
+			return false
+		}
+		if pattern == "*" {
+			return true
+		}
+		matched, _ := path.Match(pattern, value)
+		return matched
+	}
+}
+
+// PkgPath matches values whose type is declared in a package matching pattern.
+// As with the go command, a pattern ending in "/..." also matches every package below it.
+func PkgPath(pattern string) generator.Predicate {
+	return func(t *generator.Matcher) bool {
+		return t.InPackage(pattern)
+	}
+}
+
+// Under matches values with any ancestor matched by p.
+func Under(p generator.Predicate) generator.Predicate {
+	return func(t *generator.Matcher) bool {
+		return t.FindAncestor(p) != nil
+	}
+}
+
+// MapKey matches map keys.
+func MapKey() generator.Predicate {
+	return (*generator.Matcher).IsAMapKey
+}
+
+// MapElement matches map elements.
+func MapElement() generator.Predicate {
+	return (*generator.Matcher).IsAMapElement
+}
+
+// SliceElement matches slice elements.
+func SliceElement() generator.Predicate {
+	return (*generator.Matcher).IsASliceElement
+}
+
+// ArrayElement matches array elements.
+func ArrayElement() generator.Predicate {
+	return (*generator.Matcher).IsAnArrayElement
+}
+
+// And matches when every one of ps matches.
+func And(ps ...generator.Predicate) generator.Predicate {
+	return func(t *generator.Matcher) bool {
+		for _, p := range ps {
+			if !p(t) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches when any one of ps matches.
+func Or(ps ...generator.Predicate) generator.Predicate {
+	return func(t *generator.Matcher) bool {
+		for _, p := range ps {
+			if p(t) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches when p does not.
+func Not(p generator.Predicate) generator.Predicate {
+	return func(t *generator.Matcher) bool {
+		return !p(t)
+	}
+}
@@ -0,0 +1,94 @@
+package match_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/merlincox/reflective/generator/match"
+	"github.com/stretchr/testify/assert"
+)
+
+type Celsius float64
+
+type Account struct {
+	ID      int     `db:"id"`
+	Owner   string  `db:"owner"`
+	Alias   *string `db:"alias"`
+	Note    string
+	Temp    Celsius
+	Balance float64
+	Tags    map[string]int
+	Address *Address `pii:"true"`
+}
+
+type Address struct {
+	Street string
+	Number int
+}
+
+func TestPredicates(t *testing.T) {
+	subject := generator.New()
+	subject, err := subject.WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.WithStringWhen(match.And(match.Tag("db", "*"), match.Kind(reflect.String)), "tagged"),
+		generator.WithStringWhen(match.Under(match.Tag("pii", "true")), "secret"),
+		generator.WithStringWhen(match.MapKey(), "key"),
+		generator.WithIntRangeWhen(match.FieldOf[Account]("ID"), 7, 7),
+		generator.WithIntRangeWhen(match.Or(match.MapElement(), match.FieldOf[Address]("Number")), 3, 3),
+		generator.WithFloat64RangeWhen(match.Type[Celsius](), -5, -5),
+		generator.WithFloat64RangeWhen(match.And(match.Kind(reflect.Float64), match.Not(match.Type[Celsius]())), 9, 9),
+	)
+	assert.Nil(t, err)
+
+	a := new(Account)
+	assert.Nil(t, subject.Fill(a))
+	assert.Equal(t, 7, a.ID)
+	assert.Equal(t, "tagged", a.Owner)
+	assert.Equal(t, "tagged", *a.Alias)
+	assert.NotEqual(t, "tagged", a.Note)
+	assert.Equal(t, Celsius(-5), a.Temp)
+	assert.Equal(t, float64(9), a.Balance)
+	assert.Equal(t, map[string]int{"key": 3}, a.Tags)
+	assert.Equal(t, "secret", a.Address.Street)
+	assert.Equal(t, 3, a.Address.Number)
+}
+
+func TestPkgPath(t *testing.T) {
+	subject := generator.New()
+	subject, err := subject.WithOptions(
+		generator.WithFloat64RangeWhen(match.PkgPath("github.com/merlincox"), 1000, 1000),
+		generator.WithFloat64RangeWhen(match.PkgPath("github.com/merlincox/..."), 1, 1),
+	)
+	assert.Nil(t, err)
+
+	a := new(Account)
+	assert.Nil(t, subject.Fill(a))
+	assert.Equal(t, Celsius(1), a.Temp)
+	assert.NotEqual(t, float64(1), a.Balance)
+}
+
+func TestRangeWhen(t *testing.T) {
+	subject, err := generator.New().WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.WithRangeWhen(match.Under(match.Tag("pii", "true")), 4, 4),
+		generator.WithRangeWhen(match.Any(), Celsius(2), Celsius(2)),
+	)
+	assert.Nil(t, err)
+
+	a := new(Account)
+	assert.Nil(t, subject.Fill(a))
+	assert.Equal(t, Celsius(2), a.Temp)
+	assert.NotEqual(t, float64(2), a.Balance)
+	assert.Equal(t, 4, a.Address.Number)
+
+	_, err = generator.New().WithOptions(generator.WithRangeWhen(match.Any(), Celsius(2), Celsius(1)))
+	assert.NotNil(t, err)
+}
+
+func TestWhenErrors(t *testing.T) {
+	_, err := generator.New().WithOptions(generator.WithIntRangeWhen(match.Any(), 5, 3))
+	assert.NotNil(t, err)
+	_, err = generator.New().WithOptions(generator.WithBoolTrueRatioWhen(match.Any(), 2))
+	assert.NotNil(t, err)
+}
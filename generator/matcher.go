@@ -1,13 +1,17 @@
 package generator
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 )
 
 type Matcher struct {
 	parent          *Matcher
 	rtype           reflect.Type
 	field           *reflect.StructField
+	owner           reflect.Value
+	required        bool
 	index           int
 	isMapKey        bool
 	isMapElement    bool
@@ -37,19 +41,186 @@ func (t *Matcher) MatchesA(a any) bool {
 	return matches(t.rtype, reflect.TypeOf(a))
 }
 
+// MatchesType reports whether the matched type is rtype, or a pointer to it.
+func (t *Matcher) MatchesType(rtype reflect.Type) bool {
+	return matches(t.rtype, rtype)
+}
+
+// MatchesAFieldOf reports whether the matched value is held in one of the named fields of the type of a, including
+// fields promoted to it through embedded structs.
 func (t *Matcher) MatchesAFieldOf(a any, names ...string) bool {
 	if t.parent == nil || t.parent.field == nil {
 		return false
 	}
-	if !matches(t.parent.rtype, reflect.TypeOf(a)) {
+	owner := reflect.TypeOf(a)
+	name := t.parent.field.Name
+	for f, depth := t.parent, 1; f != nil; f, depth = f.embeddedIn(), depth+1 {
+		if !matches(f.rtype, owner) {
+			continue
+		}
+		// a field is promoted only if it is not shadowed by a shallower one of the same name
+		if field, ok := indirect(owner).FieldByName(name); !ok || len(field.Index) != depth {
+			return false
+		}
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
 		return false
 	}
-	for _, name := range names {
-		if t.parent.field.Name == name {
-			return true
+	return false
+}
+
+// embeddedIn returns the field matcher of the embedded field holding the struct which owns the field of t, through
+// which that field is promoted, or nil if there is none.
+func (t *Matcher) embeddedIn() *Matcher {
+	p := t.parent
+	if p != nil && p.field == nil && p.rtype.Kind() == reflect.Pointer && p.rtype.Elem() == t.rtype {
+		p = p.parent
+	}
+	if p == nil || p.field == nil || !p.field.Anonymous || indirect(p.field.Type) != t.rtype {
+		return nil
+	}
+	return p
+}
+
+// IsEmbedded reports whether the matched value is held in an embedded (anonymous) struct field.
+func (t *Matcher) IsEmbedded() bool {
+	field := t.Field()
+	return field != nil && field.Anonymous
+}
+
+// Field returns the struct field in which the matched value is held, either directly or through pointers, or nil if it
+// is not held in a struct field.
+func (t *Matcher) Field() *reflect.StructField {
+	if holder := t.holder(); holder != nil {
+		return holder.field
+	}
+	return nil
+}
+
+// holder returns the matcher of the struct field in which the matched value is held, either directly or through
+// pointers, or nil if there is none.
+func (t *Matcher) holder() *Matcher {
+	if t == nil || t.field != nil {
+		return t
+	}
+	for p := t.parent; p != nil; p = p.parent {
+		if p.field != nil {
+			return p
+		}
+		// only the matchers of pointer values, made by forSimpleType, lie between a field and what it points to
+		if p.name == "" || p.rtype.Kind() != reflect.Pointer {
+			return nil
 		}
 	}
-	return false
+	return nil
+}
+
+// Sibling returns the value of the named field of the struct holding the matched value, as generated so far, so that
+// the matched value may depend on fields generated before it. Fields are generated in the order of their declaration,
+// unless ordered by WithFieldOrder or a reflective:"after=Name" tag. The matched value may be held in a field directly
+// or through pointers. The bool result is false if the matched value is not held in a struct field, the struct has no
+// such exported field, or the field is promoted through an embedded pointer which is nil.
+func (t *Matcher) Sibling(name string) (any, bool) {
+	holder := t.holder()
+	if holder == nil || !holder.owner.IsValid() {
+		return nil, false
+	}
+	field, ok := holder.owner.Type().FieldByName(name)
+	if !ok {
+		return nil, false
+	}
+	sibling, err := holder.owner.FieldByIndexErr(field.Index)
+	if err != nil || !sibling.CanInterface() {
+		return nil, false
+	}
+	return sibling.Interface(), true
+}
+
+// isRequired reports whether the matched value is held in a field which must not be left nil or zero, either directly
+// or as the value of that field.
+func (t *Matcher) isRequired() bool {
+	if t == nil {
+		return false
+	}
+	return t.required || t.field == nil && t.parent != nil && t.parent.required
+}
+
+// FieldName returns the name of the struct field in which the matched value is held, or "" if there is none.
+func (t *Matcher) FieldName() string {
+	if field := t.Field(); field != nil {
+		return field.Name
+	}
+	return ""
+}
+
+// Tag returns the value associated with key in the tag of the struct field in which the matched value is held.
+// The bool result reports whether the key was present.
+func (t *Matcher) Tag(key string) (string, bool) {
+	if field := t.Field(); field != nil {
+		return field.Tag.Lookup(key)
+	}
+	return "", false
+}
+
+// Depth returns the number of ancestors of the matcher, which is zero for the root.
+func (t *Matcher) Depth() int {
+	depth := 0
+	for p := t.parent; p != nil; p = p.parent {
+		depth++
+	}
+	return depth
+}
+
+// Root returns the outermost ancestor of the matcher, or the matcher itself if it has no parent.
+func (t *Matcher) Root() *Matcher {
+	root := t
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root
+}
+
+// Ancestors returns the ancestors of the matcher, nearest first.
+func (t *Matcher) Ancestors() []*Matcher {
+	ancestors := make([]*Matcher, 0, t.Depth())
+	for p := t.parent; p != nil; p = p.parent {
+		ancestors = append(ancestors, p)
+	}
+	return ancestors
+}
+
+// FindAncestor returns the nearest ancestor for which pred returns true, or nil if there is none.
+func (t *Matcher) FindAncestor(pred func(t *Matcher) bool) *Matcher {
+	for p := t.parent; p != nil; p = p.parent {
+		if pred(p) {
+			return p
+		}
+	}
+	return nil
+}
+
+// Implements reports whether the matched type, or a pointer to it, implements the interface type ifaceType.
+func (t *Matcher) Implements(ifaceType reflect.Type) bool {
+	if ifaceType == nil || ifaceType.Kind() != reflect.Interface {
+		return false
+	}
+	return t.rtype.Implements(ifaceType) || reflect.PointerTo(t.rtype).Implements(ifaceType)
+}
+
+// InPackage reports whether the matched type, or the type it points to, is declared in a package matching pattern.
+// As with the go command, a pattern ending in "/..." also matches every package below it.
+func (t *Matcher) InPackage(pattern string) bool {
+	return pkgPathMatches(pattern, indirect(t.rtype).PkgPath())
+}
+
+func pkgPathMatches(pattern, pkgPath string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/")
+	}
+	return pkgPath == pattern
 }
 
 func (t *Matcher) IsAMapKey() bool {
@@ -68,6 +239,14 @@ func (t *Matcher) IsAnArrayElement() bool {
 	return t.parent != nil && t.parent.isArrayElement
 }
 
+func (t *Matcher) IsAMapLen() bool {
+	return t.isMapLen
+}
+
+func (t *Matcher) IsASliceLen() bool {
+	return t.isSliceLen
+}
+
 func (t *Matcher) IsARealPart() bool {
 	return t.isRealPart
 }
@@ -100,6 +279,36 @@ func (t *Matcher) HasParent() bool {
 	return t.parent != nil
 }
 
+// Path returns the location of the matched value within the value being filled, using Go selector and index syntax,
+// such as Orders[2].Address.Street or Scripts[Latin]. Map keys, lengths and the parts of complex numbers, which are
+// not addressable in Go, are denoted by the suffixes #key, #len, #real and #imag. The path of the root is "".
+func (t *Matcher) Path() string {
+	if t == nil {
+		return ""
+	}
+	path := t.parent.Path()
+	switch {
+	case t.field != nil:
+		if path == "" {
+			return t.field.Name
+		}
+		return path + "." + t.field.Name
+	case t.isSliceElement, t.isArrayElement:
+		return fmt.Sprintf("%s[%d]", path, t.index)
+	case t.isMapElement:
+		return fmt.Sprintf("%s[%v]", path, t.mapKeyValue)
+	case t.isMapKey:
+		return path + "#key"
+	case t.isMapLen, t.isSliceLen:
+		return path + "#len"
+	case t.isRealPart:
+		return path + "#real"
+	case t.isImaginaryPart:
+		return path + "#imag"
+	}
+	return path
+}
+
 func (t *Matcher) forSimpleType(current reflect.Type) *Matcher {
 	return &Matcher{
 		rtype:  current,
@@ -108,10 +317,12 @@ func (t *Matcher) forSimpleType(current reflect.Type) *Matcher {
 	}
 }
 
-func (t *Matcher) forField(current reflect.Type, field reflect.StructField) *Matcher {
+func (t *Matcher) forField(owner reflect.Value, i int) *Matcher {
+	field := owner.Type().Field(i)
 	return &Matcher{
-		rtype:  current,
+		rtype:  owner.Type(),
 		field:  &field,
+		owner:  owner,
 		parent: t,
 	}
 }
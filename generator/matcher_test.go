@@ -1,8 +1,10 @@
 package generator_test
 
 import (
+	"fmt"
 	"github.com/merlincox/reflective/generator"
 	"github.com/stretchr/testify/assert"
+	"reflect"
 	"strings"
 	"testing"
 	"unsafe"
@@ -167,3 +169,146 @@ func TestNonPointer(t *testing.T) {
 	err := generator.New().Fill(example)
 	assert.NotNil(t, err)
 }
+
+type Tagged struct {
+	Personal Personal `pii:"true"`
+	Public   Personal
+}
+
+type Personal struct {
+	Name  string  `db:"name"`
+	Nick  *string `db:"nick"`
+	Names []string
+	Code  custstring
+}
+
+type custstring string
+
+func (c custstring) String() string {
+	return string(c)
+}
+
+func TestIntrospection(t *testing.T) {
+
+	subject := generator.New()
+
+	stringer := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	var tags []string
+
+	subject, _ = subject.WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.WithStringFn(
+			func(t *generator.Matcher) (string, bool) {
+				if t.Implements(stringer) {
+					return "CODE", true
+				}
+				if tag, ok := t.Tag("db"); ok {
+					tags = append(tags, tag+":"+t.FieldName())
+				}
+				pii := t.FindAncestor(func(a *generator.Matcher) bool {
+					tag, _ := a.Tag("pii")
+					return tag == "true"
+				})
+				if pii != nil {
+					return "REDACTED", true
+				}
+				return "", false
+			}),
+		generator.WithSliceLengthFn(
+			func(t *generator.Matcher) (int, int, bool) {
+				if t.IsASliceLen() && t.FieldName() == "Names" {
+					return 3, 3, true
+				}
+				return 0, 0, false
+			}),
+	)
+	example := new(Tagged)
+	assert.Nil(t, subject.Fill(example))
+	assert.Equal(t, "REDACTED", example.Personal.Name)
+	assert.NotEqual(t, "REDACTED", example.Public.Name)
+	assert.Equal(t, []string{"REDACTED", "REDACTED", "REDACTED"}, example.Personal.Names)
+	assert.Len(t, example.Public.Names, 3)
+	assert.Equal(t, custstring("CODE"), example.Personal.Code)
+	assert.Equal(t, "REDACTED", *example.Personal.Nick)
+	assert.Equal(t, []string{"name:Name", "nick:Nick", "name:Name", "nick:Nick"}, tags)
+}
+
+func TestAncestry(t *testing.T) {
+
+	subject := generator.New()
+
+	var depth int
+	var ancestors []*generator.Matcher
+	var root *generator.Matcher
+
+	subject, _ = subject.WithOptions(
+		generator.WithStringFn(
+			func(t *generator.Matcher) (string, bool) {
+				if root == nil && t.IsASliceElement() {
+					depth = t.Depth()
+					ancestors = t.Ancestors()
+					root = t.Root()
+				}
+				return "", false
+			}),
+	)
+	example := new(Tagged)
+	assert.Nil(t, subject.Fill(example))
+	assert.Equal(t, len(ancestors), depth)
+	assert.Equal(t, root, ancestors[len(ancestors)-1])
+	assert.False(t, root.HasParent())
+	assert.Equal(t, "Personal", root.FieldName())
+	assert.Equal(t, 0, root.Depth())
+}
+
+type Base struct {
+	ID      int
+	Created string
+}
+
+type Audit struct {
+	Reviewer string
+}
+
+type Embedding struct {
+	Base
+	*Audit
+	Created string
+}
+
+func TestEmbedding(t *testing.T) {
+	var promoted, shadowed, embedded []string
+	g, err := generator.New().WithOptions(
+		generator.WithPointerNilRatio(1),
+		generator.WithNonNilEmbeddedPointers(),
+		generator.WithStringFn(func(m *generator.Matcher) (string, bool) {
+			if m.MatchesAFieldOf(Embedding{}, "ID", "Reviewer", "Created") {
+				promoted = append(promoted, m.Path())
+			}
+			return "", false
+		}),
+		generator.WithIntFn(func(m *generator.Matcher) (int, int, bool) {
+			if m.MatchesAFieldOf(&Embedding{}, "ID") {
+				promoted = append(promoted, m.Path())
+			}
+			if m.MatchesAFieldOf(Base{}, "ID") {
+				shadowed = append(shadowed, m.Path())
+			}
+			return 0, 0, false
+		}),
+		generator.WithPointerNilRatioFn(func(m *generator.Matcher) (float64, bool) {
+			if m.IsEmbedded() {
+				embedded = append(embedded, m.Path())
+			}
+			return 0, false
+		}),
+	)
+	assert.Nil(t, err)
+
+	var e Embedding
+	assert.Nil(t, g.Fill(&e))
+	assert.NotNil(t, e.Audit)
+	assert.Equal(t, []string{"Base.ID", "Audit.Reviewer", "Created"}, promoted)
+	assert.Equal(t, []string{"Base.ID"}, shadowed)
+	assert.Equal(t, []string{"Audit"}, embedded)
+}
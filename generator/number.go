@@ -0,0 +1,142 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Number is satisfied by every integer and floating-point type, including named types such as
+//
+//	type Celsius float64
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
+}
+
+// WithRange sets the range of values of type T. Where T is a named type, such as Celsius, the range applies only to
+// that type, and other types of the same kind keep the range set for the kind.
+func WithRange[T Number](min, max T) Option {
+	return rangeOf("WithRange", nil, min, max)
+}
+
+// WithRangeWhen sets the range of values of type T matched by p, or of every value of type T if p is nil. Where T is a
+// named type, such as Celsius, the range applies only to that type.
+func WithRangeWhen[T Number](p Predicate, min, max T) Option {
+	if p == nil {
+		p = func(t *Matcher) bool {
+			return true
+		}
+	}
+	return rangeOf("WithRangeWhen", p, min, max)
+}
+
+func rangeOf[T Number](name string, p Predicate, min, max T) Option {
+	rtype := reflect.TypeOf(min)
+	switch rtype.Kind() {
+	case reflect.Int:
+		return typedRange(name, rtype, p, int(min), int(max))
+	case reflect.Int8:
+		return typedRange(name, rtype, p, int8(min), int8(max))
+	case reflect.Int16:
+		return typedRange(name, rtype, p, int16(min), int16(max))
+	case reflect.Int32:
+		return typedRange(name, rtype, p, int32(min), int32(max))
+	case reflect.Int64:
+		return typedRange(name, rtype, p, int64(min), int64(max))
+	case reflect.Uint:
+		return typedRange(name, rtype, p, uint(min), uint(max))
+	case reflect.Uint8:
+		return typedRange(name, rtype, p, uint8(min), uint8(max))
+	case reflect.Uint16:
+		return typedRange(name, rtype, p, uint16(min), uint16(max))
+	case reflect.Uint32:
+		return typedRange(name, rtype, p, uint32(min), uint32(max))
+	case reflect.Uint64:
+		return typedRange(name, rtype, p, uint64(min), uint64(max))
+	case reflect.Float32:
+		return typedRange(name, rtype, p, float32(min), float32(max))
+	}
+	return typedRange(name, rtype, p, float64(min), float64(max))
+}
+
+// WithRangeFn registers a function for setting the range of values of type T within a matched context. Where T is a
+// named type the function is only consulted for that type.
+func WithRangeFn[T Number](fn func(t *Matcher) (T, T, bool)) Option {
+	return rangeFnOf("WithRangeFn", func(t *Matcher) (T, T, bool, error) {
+		min, max, ok := fn(t)
+		return min, max, ok, nil
+	})
+}
+
+// WithRangeFnE is like WithRangeFn, but fn may return an error, which stops the fill and is returned by Fill.
+func WithRangeFnE[T Number](fn func(t *Matcher) (T, T, bool, error)) Option {
+	return rangeFnOf("WithRangeFnE", fn)
+}
+
+func rangeFnOf[T Number](name string, fn func(t *Matcher) (T, T, bool, error)) Option {
+	rtype := reflect.TypeOf(T(0))
+	switch rtype.Kind() {
+	case reflect.Int:
+		return typedRangeFn[int](name, rtype, fn)
+	case reflect.Int8:
+		return typedRangeFn[int8](name, rtype, fn)
+	case reflect.Int16:
+		return typedRangeFn[int16](name, rtype, fn)
+	case reflect.Int32:
+		return typedRangeFn[int32](name, rtype, fn)
+	case reflect.Int64:
+		return typedRangeFn[int64](name, rtype, fn)
+	case reflect.Uint:
+		return typedRangeFn[uint](name, rtype, fn)
+	case reflect.Uint8:
+		return typedRangeFn[uint8](name, rtype, fn)
+	case reflect.Uint16:
+		return typedRangeFn[uint16](name, rtype, fn)
+	case reflect.Uint32:
+		return typedRangeFn[uint32](name, rtype, fn)
+	case reflect.Uint64:
+		return typedRangeFn[uint64](name, rtype, fn)
+	case reflect.Float32:
+		return typedRangeFn[float32](name, rtype, fn)
+	}
+	return typedRangeFn[float64](name, rtype, fn)
+}
+
+func isKindType(rtype reflect.Type) bool {
+	return rtype.PkgPath() == "" && rtype.Name() == rtype.Kind().String()
+}
+
+// typedRange returns the option setting the range of values of rtype, as K, which is its kind. Without a predicate the
+// range is recorded in the Config.
+func typedRange[K numeric](name string, rtype reflect.Type, p Predicate, min, max K) Option {
+	source := fmt.Sprintf("%s[%s]", name, rtype)
+	if p != nil {
+		when := p
+		if !isKindType(rtype) {
+			when = func(t *Matcher) bool {
+				return t.rtype == rtype && p(t)
+			}
+		}
+		return rangeRule(source, matchLevel, when, min, max)
+	}
+	if isKindType(rtype) {
+		return recorded(rangeRule(source, kindLevel, nil, min, max), func(c *Config) {
+			c.setRange(rtype.Kind().String(), decimalOf(min), decimalOf(max))
+		})
+	}
+	return recorded(rangeRule(source, typeLevel, func(t *Matcher) bool {
+		return t.rtype == rtype
+	}, min, max), func(c *Config) {
+		c.Rules = append(c.Rules, Rule{Type: rtype.String(), Range: &Range{Min: decimalOf(min), Max: decimalOf(max)}})
+	})
+}
+
+func typedRangeFn[K numeric, T Number](name string, rtype reflect.Type, fn func(t *Matcher) (T, T, bool, error)) Option {
+	anyOfKind := isKindType(rtype)
+	return rangeFnRule(fmt.Sprintf("%s[%s]", name, rtype), func(t *Matcher) (K, K, bool, error) {
+		if !anyOfKind && t.rtype != rtype {
+			return 0, 0, false, nil
+		}
+		min, max, ok, err := fn(t)
+		return K(min), K(max), ok, err
+	})
+}
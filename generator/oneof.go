@@ -0,0 +1,203 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// oneOfGroup is a group of mutually exclusive fields of a struct, of which exactly one is populated.
+type oneOfGroup struct {
+	fields  []int
+	weights []float64
+}
+
+// WithOneOf makes the named fields of the struct type T mutually exclusive, so that exactly one of them, chosen with
+// equal chance, is populated and the others are left zero, as for a protobuf oneof. The fields must be pointers,
+// slices, maps or interfaces; the chosen one is never nil, except for an interface, which is populated only by Set or
+// a rule for it. Fields may also be grouped with a reflective:"oneof=group" tag, optionally weighted with weight=n. A
+// field belongs to at most one group.
+func WithOneOf[T any](names ...string) Option {
+	weights := make(map[string]float64, len(names))
+	for _, name := range names {
+		weights[name] = 1
+	}
+	return oneOfRule[T]("WithOneOf", weights, len(names))
+}
+
+// WithOneOfWeights is like WithOneOf, but chooses each field with a chance proportional to its weight.
+func WithOneOfWeights[T any](weights map[string]float64) Option {
+	return oneOfRule[T]("WithOneOfWeights", weights, len(weights))
+}
+
+func oneOfRule[T any](name string, weights map[string]float64, n int) Option {
+	return func(g *generator) (*generator, error) {
+		rtype := reflect.TypeOf((*T)(nil)).Elem()
+		source := fmt.Sprintf("%s[%s]", name, rtype)
+		if rtype.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%s: not a struct type", source)
+		}
+		if len(weights) < 2 || len(weights) != n {
+			return nil, fmt.Errorf("%s: at least two distinct fields are required", source)
+		}
+		groups, ok := g.oneOfs[rtype]
+		if !ok {
+			var err error
+			if groups, err = taggedOneOfs(rtype); err != nil {
+				return nil, fmt.Errorf("%s: %w", source, err)
+			}
+		}
+		var group oneOfGroup
+		for name, weight := range weights {
+			field, ok := rtype.FieldByName(name)
+			if !ok || len(field.Index) != 1 {
+				return nil, fmt.Errorf("%s: no field %s", source, name)
+			}
+			if err := validateOneOf(field, weight); err != nil {
+				return nil, fmt.Errorf("%s: %w", source, err)
+			}
+			group.fields = append(group.fields, field.Index[0])
+		}
+		sort.Ints(group.fields)
+		for _, i := range group.fields {
+			group.weights = append(group.weights, weights[rtype.Field(i).Name])
+		}
+		groups = append(groups[:len(groups):len(groups)], group)
+		if err := disjoint(rtype, groups); err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		oneOfs := make(map[reflect.Type][]oneOfGroup, len(g.oneOfs)+1)
+		for key, value := range g.oneOfs {
+			oneOfs[key] = value
+		}
+		oneOfs[rtype] = groups
+		g.oneOfs = oneOfs
+		return g, nil
+	}
+}
+
+func validateOneOf(field reflect.StructField, weight float64) error {
+	switch field.Type.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+	default:
+		return fmt.Errorf("field %s is not a pointer, slice, map or interface", field.Name)
+	}
+	if !(weight > 0) {
+		return fmt.Errorf("field %s must have a positive weight", field.Name)
+	}
+	return nil
+}
+
+// disjoint returns an error if a field of a struct type belongs to more than one group.
+func disjoint(rtype reflect.Type, groups []oneOfGroup) error {
+	grouped := make(map[int]bool)
+	for _, group := range groups {
+		for _, i := range group.fields {
+			if grouped[i] {
+				return fmt.Errorf("field %s belongs to more than one group", rtype.Field(i).Name)
+			}
+			grouped[i] = true
+		}
+	}
+	return nil
+}
+
+// oneOfGroups returns the groups of mutually exclusive fields of a struct type, set by WithOneOf or by tags.
+func (g *generator) oneOfGroups(rtype reflect.Type) ([]oneOfGroup, error) {
+	if groups, ok := g.oneOfs[rtype]; ok {
+		return groups, nil
+	}
+	groups, err := taggedOneOfs(rtype)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rtype, err)
+	}
+	return groups, nil
+}
+
+// taggedGroups caches the groups of mutually exclusive fields of struct types set by tags, as taggedOneOf by
+// reflect.Type.
+var taggedGroups sync.Map
+
+type taggedOneOf struct {
+	groups []oneOfGroup
+	err    error
+}
+
+// taggedOneOfs returns the groups of mutually exclusive fields of a struct type set by tags.
+func taggedOneOfs(rtype reflect.Type) ([]oneOfGroup, error) {
+	if cached, ok := taggedGroups.Load(rtype); ok {
+		return cached.(taggedOneOf).groups, cached.(taggedOneOf).err
+	}
+	groups, err := readOneOfTags(rtype)
+	taggedGroups.Store(rtype, taggedOneOf{groups: groups, err: err})
+	return groups, err
+}
+
+// readOneOfTags reads the groups of mutually exclusive fields of a struct type from the oneof and weight tags of its
+// fields.
+func readOneOfTags(rtype reflect.Type) ([]oneOfGroup, error) {
+	var groups []oneOfGroup
+	index := make(map[string]int)
+	for i := 0; i < rtype.NumField(); i++ {
+		field := rtype.Field(i)
+		names := tagValues(field, "oneof")
+		if len(names) == 0 {
+			continue
+		}
+		if len(names) > 1 {
+			return nil, fmt.Errorf("field %s belongs to more than one group", field.Name)
+		}
+		weight := 1.0
+		if values := tagValues(field, "weight"); len(values) != 0 {
+			var err error
+			if weight, err = strconv.ParseFloat(values[0], 64); err != nil {
+				return nil, fmt.Errorf("field %s has an invalid weight: %w", field.Name, err)
+			}
+		}
+		if err := validateOneOf(field, weight); err != nil {
+			return nil, err
+		}
+		j, ok := index[names[0]]
+		if !ok {
+			j = len(groups)
+			index[names[0]] = j
+			groups = append(groups, oneOfGroup{})
+		}
+		groups[j].fields = append(groups[j].fields, i)
+		groups[j].weights = append(groups[j].weights, weight)
+	}
+	return groups, nil
+}
+
+// chooseOneOfs chooses the field to populate in each group of mutually exclusive fields of a struct type, returning
+// the chosen fields and the fields to leave zero.
+func (g *generator) chooseOneOfs(rtype reflect.Type) (chosen, excluded map[int]bool, err error) {
+	groups, err := g.oneOfGroups(rtype)
+	if err != nil || len(groups) == 0 {
+		return nil, nil, err
+	}
+	chosen, excluded = make(map[int]bool), make(map[int]bool)
+	for _, group := range groups {
+		total := 0.0
+		for _, weight := range group.weights {
+			total += weight
+		}
+		choice := len(group.fields) - 1
+		for r, j := g.Float64()*total, 0; j < len(group.fields); j++ {
+			if r -= group.weights[j]; r < 0 {
+				choice = j
+				break
+			}
+		}
+		for j, i := range group.fields {
+			if j == choice {
+				chosen[i] = true
+			} else {
+				excluded[i] = true
+			}
+		}
+	}
+	return chosen, excluded, nil
+}
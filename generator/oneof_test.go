@@ -0,0 +1,99 @@
+package generator_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type Contact struct {
+	Email *string           `reflective:"oneof=contact"`
+	Phone []string          `reflective:"oneof=contact,weight=3"`
+	Other map[string]string `reflective:"oneof=contact"`
+	Card  *Address
+	Bank  *Address
+	Note  string
+}
+
+type Untyped struct {
+	Value any  `reflective:"oneof=value"`
+	Other *int `reflective:"oneof=value"`
+}
+
+type Overlapping struct {
+	First  *int `reflective:"oneof=a,oneof=b"`
+	Second *int `reflective:"oneof=a"`
+	Third  *int `reflective:"oneof=b"`
+}
+
+func TestOneOf(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithPointerNilRatio(1),
+		generator.WithSliceNilRatio(1),
+		generator.WithMapNilRatio(1),
+		generator.WithOneOf[Contact]("Card", "Bank"),
+	)
+	assert.Nil(t, err)
+
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		var c Contact
+		assert.Nil(t, g.Fill(&c))
+		populated := map[string]bool{"Email": c.Email != nil, "Phone": c.Phone != nil, "Other": c.Other != nil}
+		set := 0
+		for name, ok := range populated {
+			if ok {
+				set++
+				counts[name]++
+			}
+		}
+		assert.Equal(t, 1, set, fmt.Sprintf("%+v", c))
+		assert.True(t, (c.Card == nil) != (c.Bank == nil))
+		assert.NotZero(t, c.Note)
+	}
+	assert.Greater(t, counts["Phone"], counts["Email"])
+	assert.Greater(t, counts["Phone"], counts["Other"])
+
+	_, err = generator.New().WithOptions(generator.WithOneOf[Contact]("Card"))
+	assert.NotNil(t, err)
+	_, err = generator.New().WithOptions(generator.WithOneOf[Contact]("Card", "Note"))
+	assert.NotNil(t, err)
+	_, err = generator.New().WithOptions(generator.WithOneOfWeights[Contact](map[string]float64{"Card": 1, "Bank": 0}))
+	assert.NotNil(t, err)
+}
+
+func TestOneOfInterface(t *testing.T) {
+	counts := map[string]int{}
+	for i := 0; i < 100; i++ {
+		var u Untyped
+		assert.Nil(t, generator.New().Fill(&u, generator.Set("Value", "set")))
+		if u.Value != nil {
+			counts["Value"]++
+			assert.Equal(t, "set", u.Value)
+			assert.Nil(t, u.Other)
+		} else {
+			counts["Other"]++
+			assert.NotNil(t, u.Other)
+		}
+	}
+	assert.NotZero(t, counts["Value"])
+	assert.NotZero(t, counts["Other"])
+}
+
+func TestOneOfOverlap(t *testing.T) {
+	_, err := generator.New().WithOptions(generator.WithOneOf[Contact]("Email", "Card"))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "more than one group")
+
+	_, err = generator.New().WithOptions(
+		generator.WithOneOf[Contact]("Card", "Bank"),
+		generator.WithOneOf[Contact]("Bank", "Card"),
+	)
+	assert.NotNil(t, err)
+
+	err = generator.New().Fill(&Overlapping{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "more than one group")
+}
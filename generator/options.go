@@ -3,6 +3,9 @@ package generator
 import (
 	"fmt"
 	"math"
+	"reflect"
+
+	"pgregory.net/rand"
 )
 
 // WithRandomiser replaces the default implementation of the Randomiser interface (pgregory.net/rand) with another.
@@ -13,81 +16,249 @@ func WithRandomiser(rand Randomiser) Option {
 	}
 }
 
-// WithPointerNilRatio sets the probability of any pointer value being nil, where 0 means never and 1 means always
-func WithPointerNilRatio(ratio float64) Option {
+// WithSeed replaces the default randomiser with one seeded with seed, so that the values generated can be reproduced.
+func WithSeed(seed uint64) Option {
 	return func(g *generator) (*generator, error) {
-		if ratio < 0 || ratio > 1 {
-			return nil, fmt.Errorf("WithPointerNilRatio: ratio must be in range 0 to 1")
+		g.rand = rand.New(seed)
+		g.config.Seed = &seed
+		return g, nil
+	}
+}
+
+// recorded returns an option which, once o is applied, records its settings in the generator Config.
+func recorded(o Option, record func(c *Config)) Option {
+	return func(g *generator) (*generator, error) {
+		g, err := o(g)
+		if err != nil {
+			return nil, err
 		}
-		g.pointerNilRatio = &ratio
+		record(&g.config)
 		return g, nil
 	}
 }
 
+func (c *Config) setRange(key string, min, max Decimal) {
+	if c.Ranges == nil {
+		c.Ranges = make(map[string]Range)
+	}
+	c.Ranges[key] = Range{Min: min, Max: max}
+}
+
+// WithPointerNilRatio sets the probability of any pointer value being nil, where 0 means never and 1 means always
+func WithPointerNilRatio(ratio float64) Option {
+	return recorded(ratioRule("WithPointerNilRatio", kindLevel, nil, ratio, pointerNilRules), func(c *Config) {
+		c.Ratios.PointerNil = &ratio
+	})
+}
+
+// WithSliceNilRatio sets the probability of any slice value being nil rather than made, where 0 means never and 1
+// means always
+func WithSliceNilRatio(ratio float64) Option {
+	return recorded(ratioRule("WithSliceNilRatio", kindLevel, nil, ratio, sliceNilRules), func(c *Config) {
+		c.Ratios.SliceNil = &ratio
+	})
+}
+
+// WithMapNilRatio sets the probability of any map value being nil rather than made, where 0 means never and 1 means
+// always
+func WithMapNilRatio(ratio float64) Option {
+	return recorded(ratioRule("WithMapNilRatio", kindLevel, nil, ratio, mapNilRules), func(c *Config) {
+		c.Ratios.MapNil = &ratio
+	})
+}
+
+// WithZeroRatio sets the probability of any struct field being left at its zero value, whatever its kind, where 0 means
+// never and 1 means always. Fields on the way to a Set path are never left zero.
+func WithZeroRatio(ratio float64) Option {
+	return recorded(ratioRule("WithZeroRatio", kindLevel, nil, ratio, zeroRules), func(c *Config) {
+		c.Ratios.Zero = &ratio
+	})
+}
+
+// Sparse populates only k randomly chosen fields of the struct being filled, leaving its other fields at their zero
+// values, except those set by Set or on the way to a Set path. Nested structs are filled as usual.
+func Sparse(k int) Option {
+	return recorded(func(g *generator) (*generator, error) {
+		if k < 1 {
+			return nil, fmt.Errorf("sparse field count must be at least 1")
+		}
+		g.sparse = k
+		return g, nil
+	}, func(c *Config) {
+		c.Sparse = &k
+	})
+}
+
+// WithNonNilEmbeddedPointers ensures that embedded pointers to structs are never nil, so that their promoted fields
+// are always populated. More specific pointer nil rules still apply.
+func WithNonNilEmbeddedPointers() Option {
+	return ratioRule("WithNonNilEmbeddedPointers", matchLevel, func(t *Matcher) bool {
+		return t.IsEmbedded() && t.rtype.Kind() == reflect.Pointer && t.rtype.Elem().Kind() == reflect.Struct
+	}, 0, pointerNilRules)
+}
+
 // WithBoolTrueRatio sets the probability of any bool value being true, where 0 means never and 1 means always
 func WithBoolTrueRatio(ratio float64) Option {
+	return recorded(ratioRule("WithBoolTrueRatio", kindLevel, nil, ratio, boolTrueRules), func(c *Config) {
+		c.Ratios.BoolTrue = &ratio
+	})
+}
+
+// WithPointerReuseRatio sets the probability of a pointer value being set to a pointer of the same type already
+// allocated while filling the same value, where 0 means never and 1 means always whenever there is one. Reused pointers
+// produce shared references and cycles, such as a node pointing back to its parent.
+func WithPointerReuseRatio(ratio float64) Option {
+	return recorded(ratioRule("WithPointerReuseRatio", kindLevel, nil, ratio, pointerReuseRules), func(c *Config) {
+		c.Ratios.PointerReuse = &ratio
+	})
+}
+
+func pointerReuseRules(g *generator) *ruleset[float64] {
+	return &g.pointerReuse
+}
+
+func pointerNilRules(g *generator) *ruleset[float64] {
+	return &g.pointerNilRules
+}
+
+func sliceNilRules(g *generator) *ruleset[float64] {
+	return &g.sliceNilRules
+}
+
+func mapNilRules(g *generator) *ruleset[float64] {
+	return &g.mapNilRules
+}
+
+func zeroRules(g *generator) *ruleset[float64] {
+	return &g.zeroRules
+}
+
+func boolTrueRules(g *generator) *ruleset[float64] {
+	return &g.boolTrueRules
+}
+
+func ratioRule(source string, spec specificity, p Predicate, ratio float64, rules func(g *generator) *ruleset[float64]) Option {
 	return func(g *generator) (*generator, error) {
 		if ratio < 0 || ratio > 1 {
-			return nil, fmt.Errorf("WithBoolTrueRatio: ratio must be in range 0 to 1")
+			return nil, fmt.Errorf("%s: ratio must be in range 0 to 1", source)
 		}
-		g.booleanTrueRatio = &ratio
+		addRule(g, rules(g), spec, source, func(t *Matcher) (float64, bool, error) {
+			return ratio, p == nil || p(t), nil
+		})
 		return g, nil
 	}
 }
 
-func numericRange[T numeric](min, max T) Option {
+func ratioFnRule(source string, fn func(t *Matcher) (float64, bool, error), rules func(g *generator) *ruleset[float64]) Option {
 	return func(g *generator) (*generator, error) {
-		switch any(min).(type) {
-		case stringLenInt, mapLenInt, sliceLenInt:
-			if min < 0 {
-				return nil, fmt.Errorf("length may not be negative")
-			}
-		case float32, float64:
-			if math.IsNaN(float64(min)) || math.IsNaN(float64(max)) {
-				return nil, fmt.Errorf("NaN is not supported")
-			}
-			if math.IsInf(float64(min), 1) || math.IsInf(float64(max), 1) {
-				return nil, fmt.Errorf("infinity is not supported")
-			}
-			if math.IsInf(float64(min), -1) || math.IsInf(float64(max), -1) {
-				return nil, fmt.Errorf("infinity is not supported")
-			}
+		addRule(g, rules(g), matchLevel, source, fn)
+		return g, nil
+	}
+}
+
+func validateRatio(ratio float64) error {
+	if !(ratio >= 0 && ratio <= 1) {
+		return fmt.Errorf("ratio must be in range 0 to 1")
+	}
+	return nil
+}
+
+func validateRunes(runes []rune) error {
+	if len(runes) == 0 {
+		return fmt.Errorf("runes may not be empty")
+	}
+	return nil
+}
+
+func validateInterval[T numeric](mm interval[T]) error {
+	return validateRange(mm.min, mm.max)
+}
+
+func validateRange[T numeric](min, max T) error {
+	switch any(min).(type) {
+	case stringLenInt, mapLenInt, sliceLenInt:
+		if min < 0 {
+			return fmt.Errorf("length may not be negative")
+		}
+	case digitsInt:
+		if min < 1 {
+			return fmt.Errorf("digit count must be at least 1")
 		}
-		if min > max {
-			return nil, fmt.Errorf("numeric range: min may not exceed max")
+	case float32, float64:
+		if math.IsNaN(float64(min)) || math.IsNaN(float64(max)) {
+			return fmt.Errorf("NaN is not supported")
 		}
-		switch any(min).(type) {
-		case int:
-			g.intSet.interval = &interval[int]{min: int(min), max: int(max)}
-		case stringLenInt:
-			g.stringLenSet.interval = &interval[stringLenInt]{min: stringLenInt(min), max: stringLenInt(max)}
-		case mapLenInt:
-			g.mapLenSet.interval = &interval[mapLenInt]{min: mapLenInt(min), max: mapLenInt(max)}
-		case sliceLenInt:
-			g.sliceLenSet.interval = &interval[sliceLenInt]{min: sliceLenInt(min), max: sliceLenInt(max)}
-		case int8:
-			g.int8Set.interval = &interval[int8]{min: int8(min), max: int8(max)}
-		case int16:
-			g.int16Set.interval = &interval[int16]{min: int16(min), max: int16(max)}
-		case int32:
-			g.int32Set.interval = &interval[int32]{min: int32(min), max: int32(max)}
-		case int64:
-			g.int64Set.interval = &interval[int64]{min: int64(min), max: int64(max)}
-		case uint:
-			g.uintSet.interval = &interval[uint]{min: uint(min), max: uint(max)}
-		case uint8:
-			g.uint8Set.interval = &interval[uint8]{min: uint8(min), max: uint8(max)}
-		case uint16:
-			g.uint16Set.interval = &interval[uint16]{min: uint16(min), max: uint16(max)}
-		case uint32:
-			g.uint32Set.interval = &interval[uint32]{min: uint32(min), max: uint32(max)}
-		case uint64:
-			g.uint64Set.interval = &interval[uint64]{min: uint64(min), max: uint64(max)}
-		case float32:
-			g.float32Set.interval = &interval[float32]{min: float32(min), max: float32(max)}
-		case float64:
-			g.float64Set.interval = &interval[float64]{min: float64(min), max: float64(max)}
+		if math.IsInf(float64(min), 1) || math.IsInf(float64(max), 1) {
+			return fmt.Errorf("infinity is not supported")
 		}
+		if math.IsInf(float64(min), -1) || math.IsInf(float64(max), -1) {
+			return fmt.Errorf("infinity is not supported")
+		}
+	}
+	if min > max {
+		return fmt.Errorf("numeric range: min may not exceed max")
+	}
+	return nil
+}
+
+func rangesOf[T numeric](g *generator) *ruleset[interval[T]] {
+	var rules any
+	var some T
+	switch any(some).(type) {
+	case int:
+		rules = &g.intRanges
+	case stringLenInt:
+		rules = &g.stringLenRanges
+	case mapLenInt:
+		rules = &g.mapLenRanges
+	case sliceLenInt:
+		rules = &g.sliceLenRanges
+	case digitsInt:
+		rules = &g.digitsRanges
+	case int8:
+		rules = &g.int8Ranges
+	case int16:
+		rules = &g.int16Ranges
+	case int32:
+		rules = &g.int32Ranges
+	case int64:
+		rules = &g.int64Ranges
+	case uint:
+		rules = &g.uintRanges
+	case uint8:
+		rules = &g.uint8Ranges
+	case uint16:
+		rules = &g.uint16Ranges
+	case uint32:
+		rules = &g.uint32Ranges
+	case uint64:
+		rules = &g.uint64Ranges
+	case float32:
+		rules = &g.float32Ranges
+	case float64:
+		rules = &g.float64Ranges
+	}
+	return rules.(*ruleset[interval[T]])
+}
+
+func rangeRule[T numeric](source string, spec specificity, p Predicate, min, max T) Option {
+	return func(g *generator) (*generator, error) {
+		if err := validateRange(min, max); err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		addRule(g, rangesOf[T](g), spec, source, func(t *Matcher) (interval[T], bool, error) {
+			return interval[T]{min: min, max: max}, p == nil || p(t), nil
+		})
+		return g, nil
+	}
+}
+
+func rangeFnRule[T numeric](source string, fn func(t *Matcher) (T, T, bool, error)) Option {
+	return func(g *generator) (*generator, error) {
+		addRule(g, rangesOf[T](g), matchLevel, source, func(t *Matcher) (interval[T], bool, error) {
+			min, max, ok, err := fn(t)
+			return interval[T]{min: min, max: max}, ok, err
+		})
 		return g, nil
 	}
 }
@@ -103,6 +274,8 @@ func defaultInterval[T numeric]() interval[T] {
 		return interval[T]{min: T(defMinMapLen), max: T(defMaxMapLen)}
 	case sliceLenInt:
 		return interval[T]{min: T(defMinSliceLen), max: T(defMaxSliceLen)}
+	case digitsInt:
+		return interval[T]{min: T(defMinDigits), max: T(defMaxDigits)}
 	}
 	return interval[T]{min: 0, max: T(defMaxFloat)}
 }
@@ -110,218 +283,188 @@ func defaultInterval[T numeric]() interval[T] {
 // WithRunes sets the runes from which strings are constructed
 func WithRunes(runes []rune) Option {
 	return func(g *generator) (*generator, error) {
-		g.runes = runes
+		if len(runes) == 0 {
+			runes = getDefRunes()
+		}
+		addRule(g, &g.runesRules, kindLevel, "WithRunes", func(t *Matcher) ([]rune, bool, error) {
+			return runes, true, nil
+		})
+		g.config.Runes = string(runes)
 		return g, nil
 	}
 }
 
 func WithStringLengthRange(min, max int) Option {
-	return numericRange(stringLenInt(min), stringLenInt(max))
+	return recorded(rangeRule("WithStringLengthRange", kindLevel, nil, stringLenInt(min), stringLenInt(max)), func(c *Config) {
+		c.setRange("string_length", decimalOf(min), decimalOf(max))
+	})
 }
 
 func WithSliceLengthRange(min, max int) Option {
-	return numericRange(sliceLenInt(min), sliceLenInt(max))
+	return recorded(rangeRule("WithSliceLengthRange", kindLevel, nil, sliceLenInt(min), sliceLenInt(max)), func(c *Config) {
+		c.setRange("slice_length", decimalOf(min), decimalOf(max))
+	})
 }
 
 func WithMapLengthRange(min, max int) Option {
-	return numericRange(mapLenInt(min), mapLenInt(max))
+	return recorded(rangeRule("WithMapLengthRange", kindLevel, nil, mapLenInt(min), mapLenInt(max)), func(c *Config) {
+		c.setRange("map_length", decimalOf(min), decimalOf(max))
+	})
 }
 
 func WithIntRange(min, max int) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 func WithInt8Range(min, max int8) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 func WithInt16Range(min, max int16) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 func WithInt32Range(min, max int32) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 func WithInt64Range(min, max int64) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 func WithUintRange(min, max uint) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 func WithUint8Range(min, max uint8) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 func WithUint16Range(min, max uint16) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 func WithUint32Range(min, max uint32) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 func WithUint64Range(min, max uint64) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 func WithFloat32Range(min, max float32) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 func WithFloat64Range(min, max float64) Option {
-	return numericRange(min, max)
+	return WithRange(min, max)
 }
 
 // WithPointerNilRatioFn registers a function for setting the chance of a pointer value being nil.
 func WithPointerNilRatioFn(fn func(t *Matcher) (float64, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.pointerNilFns = append(g.pointerNilFns, fn)
-		return g, nil
-	}
+	return ratioFnRule("WithPointerNilRatioFn", noError(fn), pointerNilRules)
+}
+
+// WithSliceNilRatioFn registers a function for setting the chance of a slice value being nil.
+func WithSliceNilRatioFn(fn func(t *Matcher) (float64, bool)) Option {
+	return ratioFnRule("WithSliceNilRatioFn", noError(fn), sliceNilRules)
+}
+
+// WithMapNilRatioFn registers a function for setting the chance of a map value being nil.
+func WithMapNilRatioFn(fn func(t *Matcher) (float64, bool)) Option {
+	return ratioFnRule("WithMapNilRatioFn", noError(fn), mapNilRules)
+}
+
+// WithZeroRatioFn registers a function for setting the chance of a struct field being left at its zero value.
+func WithZeroRatioFn(fn func(t *Matcher) (float64, bool)) Option {
+	return ratioFnRule("WithZeroRatioFn", noError(fn), zeroRules)
 }
 
 // WithBoolTrueRatioFn registers a function for setting the chance of a boolean being true
 func WithBoolTrueRatioFn(fn func(t *Matcher) (float64, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.boolTrueFns = append(g.boolTrueFns, fn)
-		return g, nil
-	}
+	return ratioFnRule("WithBoolTrueRatioFn", noError(fn), boolTrueRules)
 }
 
 // WithRunesFn registers a function for setting the runes from which strings are constructed within a matched context
 func WithRunesFn(fn func(t *Matcher) ([]rune, bool)) Option {
 	return func(g *generator) (*generator, error) {
-		g.runesFns = append(g.runesFns, fn)
+		addRule(g, &g.runesRules, matchLevel, "WithRunesFn", noError(fn))
 		return g, nil
 	}
 }
 
 func WithIntFn(fn func(t *Matcher) (int, int, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.intSet.fns = append(g.intSet.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithInt8Fn(fn func(t *Matcher) (int8, int8, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.int8Set.fns = append(g.int8Set.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithInt16Fn(fn func(t *Matcher) (int16, int16, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.int16Set.fns = append(g.int16Set.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithInt32Fn(fn func(t *Matcher) (int32, int32, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.int32Set.fns = append(g.int32Set.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithInt64Fn(fn func(t *Matcher) (int64, int64, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.int64Set.fns = append(g.int64Set.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithUintFn(fn func(t *Matcher) (uint, uint, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.uintSet.fns = append(g.uintSet.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithUint8Fn(fn func(t *Matcher) (uint8, uint8, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.uint8Set.fns = append(g.uint8Set.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithUint16Fn(fn func(t *Matcher) (uint16, uint16, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.uint16Set.fns = append(g.uint16Set.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithUint32Fn(fn func(t *Matcher) (uint32, uint32, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.uint32Set.fns = append(g.uint32Set.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithUint64Fn(fn func(t *Matcher) (uint64, uint64, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.uint64Set.fns = append(g.uint64Set.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithFloat32Fn(fn func(t *Matcher) (float32, float32, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.float32Set.fns = append(g.float32Set.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithFloat64Fn(fn func(t *Matcher) (float64, float64, bool)) Option {
-	return func(g *generator) (*generator, error) {
-		g.float64Set.fns = append(g.float64Set.fns, fn)
-		return g, nil
-	}
+	return WithRangeFn(fn)
 }
 
 func WithStringLengthFn(fn func(t *Matcher) (int, int, bool)) Option {
-	adapter := func(t *Matcher) (stringLenInt, stringLenInt, bool) {
+	return lengthFnRule[stringLenInt]("WithStringLengthFn", func(t *Matcher) (int, int, bool, error) {
 		min, max, ok := fn(t)
-		return stringLenInt(min), stringLenInt(max), ok
-	}
-
-	return func(g *generator) (*generator, error) {
-		g.stringLenSet.fns = append(g.stringLenSet.fns, adapter)
-		return g, nil
-	}
+		return min, max, ok, nil
+	})
 }
 
 func WithSliceLengthFn(fn func(t *Matcher) (int, int, bool)) Option {
-	adapter := func(t *Matcher) (sliceLenInt, sliceLenInt, bool) {
+	return lengthFnRule[sliceLenInt]("WithSliceLengthFn", func(t *Matcher) (int, int, bool, error) {
 		min, max, ok := fn(t)
-		return sliceLenInt(min), sliceLenInt(max), ok
-	}
-
-	return func(g *generator) (*generator, error) {
-		g.sliceLenSet.fns = append(g.sliceLenSet.fns, adapter)
-		return g, nil
-	}
+		return min, max, ok, nil
+	})
 }
 
 func WithMapLengthFn(fn func(t *Matcher) (int, int, bool)) Option {
-	adapter := func(t *Matcher) (mapLenInt, mapLenInt, bool) {
+	return lengthFnRule[mapLenInt]("WithMapLengthFn", func(t *Matcher) (int, int, bool, error) {
 		min, max, ok := fn(t)
-		return mapLenInt(min), mapLenInt(max), ok
-	}
-
-	return func(g *generator) (*generator, error) {
-		g.mapLenSet.fns = append(g.mapLenSet.fns, adapter)
-		return g, nil
-	}
+		return min, max, ok, nil
+	})
 }
 
 func WithStringFn(fn func(t *Matcher) (string, bool)) Option {
 	return func(g *generator) (*generator, error) {
-		g.stringFns = append(g.stringFns, fn)
+		addRule(g, &g.stringRules, matchLevel, "WithStringFn", noError(fn))
 		return g, nil
 	}
 }
@@ -314,3 +314,141 @@ func TestErrors(t *testing.T) {
 	}
 
 }
+
+type Celsius float64
+
+type Kelvin float64
+
+type Temperatures struct {
+	Celsius Celsius
+	Kelvin  Kelvin
+	Plain   float64
+	Offset  custint
+	Count   int
+}
+
+func TestNamedRanges(t *testing.T) {
+	subject := generator.New()
+	subject, err := subject.WithOptions(
+		generator.WithRange[Celsius](-40, -40),
+		generator.WithRange[Kelvin](300, 300),
+		generator.WithFloat64Range(1000, 1000),
+		generator.WithRangeFn(func(m *generator.Matcher) (custint, custint, bool) {
+			return 7, 7, true
+		}),
+		generator.WithIntRange(500, 500),
+	)
+	assert.Nil(t, err)
+
+	e := new(Temperatures)
+	assert.Nil(t, subject.Fill(e))
+	assert.Equal(t, Celsius(-40), e.Celsius)
+	assert.Equal(t, Kelvin(300), e.Kelvin)
+	assert.Equal(t, float64(1000), e.Plain)
+	assert.Equal(t, custint(7), e.Offset)
+	assert.Equal(t, 500, e.Count)
+
+	_, err = generator.New().WithOptions(generator.WithRange[Celsius](1, -1))
+	assert.NotNil(t, err)
+}
+
+func TestNilCollections(t *testing.T) {
+	type Payload struct {
+		Tags   []string
+		Names  []string
+		Counts map[string]int
+	}
+
+	g, err := generator.New().WithOptions(
+		generator.WithSliceNilRatio(1),
+		generator.WithMapNilRatio(1),
+		generator.WithSliceNilRatioFn(func(m *generator.Matcher) (float64, bool) {
+			return 0, m.FieldName() == "Names"
+		}),
+	)
+	assert.Nil(t, err)
+
+	var payload Payload
+	assert.Nil(t, g.Fill(&payload))
+	assert.Nil(t, payload.Tags)
+	assert.NotNil(t, payload.Names)
+	assert.Nil(t, payload.Counts)
+
+	assert.Nil(t, generator.New().Fill(&payload))
+	assert.NotNil(t, payload.Tags)
+	assert.NotNil(t, payload.Counts)
+
+	_, err = generator.New().WithOptions(generator.WithMapNilRatio(-1))
+	assert.NotNil(t, err)
+}
+
+func TestZeroRatio(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.WithZeroRatio(1),
+		generator.WithZeroRatioFn(func(m *generator.Matcher) (float64, bool) {
+			return 0, m.FieldName() == "Address"
+		}),
+	)
+	assert.Nil(t, err)
+
+	user := User{Name: "stale", Age: 42}
+	assert.Nil(t, g.Fill(&user))
+	assert.Equal(t, User{}, user)
+
+	g, err = generator.New().WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.WithZeroRatioFn(func(m *generator.Matcher) (float64, bool) {
+			return 1, m.Depth() > 0
+		}),
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, g.Fill(&user))
+	assert.NotZero(t, user.Name)
+	assert.Equal(t, Address{}, user.Address)
+	assert.Equal(t, &Address{}, user.Other)
+
+	// fields on the way to a Set path are not left zero
+	g, err = generator.New().WithOptions(generator.WithZeroRatio(1))
+	assert.Nil(t, err)
+	assert.Nil(t, g.Fill(&user, generator.Set("Address.Street", "Mill Lane")))
+	assert.Equal(t, Address{Street: "Mill Lane"}, user.Address)
+	assert.Zero(t, user.Name)
+}
+
+func TestSparse(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.WithSliceLengthRange(1, 5),
+		generator.WithIntRange(1, 100),
+		generator.WithFloat64Range(1, 100),
+		generator.WithStringLengthRange(1, 5),
+		generator.Sparse(2),
+	)
+	assert.Nil(t, err)
+
+	for i := 0; i < 20; i++ {
+		var user User
+		assert.Nil(t, g.Fill(&user))
+		populated := 0
+		for _, set := range []bool{
+			user.Name != "", user.Age != 0, user.Score != 0, user.Tags != nil, user.Address != Address{}, user.Other != nil,
+		} {
+			if set {
+				populated++
+			}
+		}
+		assert.Equal(t, 2, populated)
+	}
+
+	// fields set or on the way to a Set path are populated besides those chosen
+	for i := 0; i < 20; i++ {
+		var user User
+		assert.Nil(t, g.Fill(&user, generator.Set("Address.Street", "Mill Lane"), generator.Set("Age", 30)))
+		assert.Equal(t, "Mill Lane", user.Address.Street)
+		assert.Equal(t, 30, user.Age)
+	}
+
+	_, err = generator.New().WithOptions(generator.Sparse(0))
+	assert.NotNil(t, err)
+}
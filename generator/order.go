@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// tagKey is the key of the struct tag holding options for fill, which are comma separated and take the form
+// key=value, such as reflective:"after=Country".
+const tagKey = "reflective"
+
+// tagValues returns the values of an option in the reflective tag of a field.
+func tagValues(field reflect.StructField, key string) []string {
+	tag, ok := field.Tag.Lookup(tagKey)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, option := range strings.Split(tag, ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(option), "="); ok && k == key {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// WithFieldOrder sets the order in which fields of the struct type T are generated, so that callbacks for later
+// fields may read earlier ones with Matcher.Sibling. The named fields are generated first, in the order given, and
+// then the others in the order of their declaration. A field tagged reflective:"after=Name" is generated after the
+// named field whatever the order.
+func WithFieldOrder[T any](names ...string) Option {
+	return func(g *generator) (*generator, error) {
+		rtype := reflect.TypeOf((*T)(nil)).Elem()
+		if rtype.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("WithFieldOrder[%s]: not a struct type", rtype)
+		}
+		order := make([]int, 0, rtype.NumField())
+		listed := make(map[int]bool, len(names))
+		for _, name := range names {
+			field, ok := rtype.FieldByName(name)
+			if !ok || len(field.Index) != 1 {
+				return nil, fmt.Errorf("WithFieldOrder[%s]: no field %s", rtype, name)
+			}
+			if listed[field.Index[0]] {
+				return nil, fmt.Errorf("WithFieldOrder[%s]: field %s is listed twice", rtype, name)
+			}
+			listed[field.Index[0]] = true
+			order = append(order, field.Index[0])
+		}
+		for i := 0; i < rtype.NumField(); i++ {
+			if !listed[i] {
+				order = append(order, i)
+			}
+		}
+		order, err := orderByTags(rtype, order)
+		if err != nil {
+			return nil, fmt.Errorf("WithFieldOrder[%s]: %w", rtype, err)
+		}
+		fieldOrders := make(map[reflect.Type][]int, len(g.fieldOrders)+1)
+		for key, value := range g.fieldOrders {
+			fieldOrders[key] = value
+		}
+		fieldOrders[rtype] = order
+		g.fieldOrders = fieldOrders
+		return g, nil
+	}
+}
+
+// taggedOrders caches the order of the fields of struct types not ordered by WithFieldOrder, as a taggedOrder by
+// reflect.Type, since it depends only on their tags.
+var taggedOrders sync.Map
+
+type taggedOrder struct {
+	order []int
+	err   error
+}
+
+// fieldOrder returns the indices of the fields of a struct type in the order in which they are to be generated: the
+// order set by WithFieldOrder, or else of their declaration, with fields tagged after=Name moved after the named
+// fields.
+func (g *generator) fieldOrder(rtype reflect.Type) ([]int, error) {
+	if order, ok := g.fieldOrders[rtype]; ok {
+		return order, nil
+	}
+	if cached, ok := taggedOrders.Load(rtype); ok {
+		return cached.(taggedOrder).order, cached.(taggedOrder).err
+	}
+	order := make([]int, rtype.NumField())
+	for i := range order {
+		order[i] = i
+	}
+	order, err := orderByTags(rtype, order)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", rtype, err)
+	}
+	taggedOrders.Store(rtype, taggedOrder{order: order, err: err})
+	return order, err
+}
+
+// orderByTags returns the order of the fields of a struct type with fields tagged after=Name moved after the named
+// fields.
+func orderByTags(rtype reflect.Type, order []int) ([]int, error) {
+	var after map[int][]int
+	for i := 0; i < rtype.NumField(); i++ {
+		for _, name := range tagValues(rtype.Field(i), "after") {
+			field, ok := rtype.FieldByName(name)
+			if !ok || len(field.Index) != 1 {
+				return nil, fmt.Errorf("field %s is tagged after unknown field %s", rtype.Field(i).Name, name)
+			}
+			if after == nil {
+				after = make(map[int][]int)
+			}
+			after[i] = append(after[i], field.Index[0])
+		}
+	}
+	if after == nil {
+		return order, nil
+	}
+
+	// repeatedly take the first field in order whose predecessors have all been taken
+	sorted := make([]int, 0, len(order))
+	taken := make([]bool, len(order))
+	for len(sorted) < len(order) {
+		next := -1
+		for _, i := range order {
+			if !taken[i] && allTaken(after[i], taken) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("fields are tagged after each other in a cycle")
+		}
+		taken[next] = true
+		sorted = append(sorted, next)
+	}
+	return sorted, nil
+}
+
+func allTaken(indices []int, taken []bool) bool {
+	for _, i := range indices {
+		if !taken[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package generator_test
+
+import (
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type Priced struct {
+	Price    int
+	Currency string
+}
+
+type Located struct {
+	State   string `reflective:"after=Country"`
+	Country string
+}
+
+type Quote struct {
+	Currency string
+	Price    *int
+}
+
+type Listing struct {
+	Title string
+	*Priced
+}
+
+type Cyclic struct {
+	A string `reflective:"after=B"`
+	B string `reflective:"after=A"`
+}
+
+func TestSibling(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithFieldOrder[Priced]("Currency"),
+		generator.WithStringFn(func(m *generator.Matcher) (string, bool) {
+			if m.MatchesAFieldOf(Priced{}, "Currency") {
+				return "JPY", true
+			}
+			if m.MatchesAFieldOf(Located{}, "Country") {
+				return "US", true
+			}
+			if m.MatchesAFieldOf(Located{}, "State") {
+				country, ok := m.Sibling("Country")
+				return map[string]string{"US": "Ohio"}[country.(string)], ok
+			}
+			return "", false
+		}),
+		generator.WithIntFn(func(m *generator.Matcher) (int, int, bool) {
+			if currency, ok := m.Sibling("Currency"); ok && currency == "JPY" {
+				return 1000, 1000, true
+			}
+			return 0, 0, false
+		}),
+	)
+	assert.Nil(t, err)
+
+	var p Priced
+	assert.Nil(t, g.Fill(&p))
+	assert.Equal(t, Priced{Price: 1000, Currency: "JPY"}, p)
+
+	var l Located
+	assert.Nil(t, g.Fill(&l))
+	assert.Equal(t, Located{State: "Ohio", Country: "US"}, l)
+
+	for i := 0; i < 2; i++ {
+		err = g.Fill(&Cyclic{})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "cycle")
+	}
+	_, err = generator.New().WithOptions(generator.WithFieldOrder[Cyclic]("A"))
+	assert.NotNil(t, err)
+
+	_, err = generator.New().WithOptions(generator.WithFieldOrder[Priced]("Missing"))
+	assert.NotNil(t, err)
+}
+
+func TestSiblingThroughNilEmbedded(t *testing.T) {
+	found := false
+	g, err := generator.New().WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.WithStringFn(func(m *generator.Matcher) (string, bool) {
+			if m.MatchesAFieldOf(Listing{}, "Title") {
+				_, found = m.Sibling("Currency")
+			}
+			return "", false
+		}),
+	)
+	assert.Nil(t, err)
+
+	var l Listing
+	assert.Nil(t, g.Fill(&l))
+	assert.False(t, found)
+	assert.NotNil(t, l.Priced)
+}
+
+func TestSiblingThroughPointer(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.WithStringFn(func(m *generator.Matcher) (string, bool) {
+			return "JPY", m.MatchesAFieldOf(Quote{}, "Currency")
+		}),
+		generator.WithIntFn(func(m *generator.Matcher) (int, int, bool) {
+			if currency, ok := m.Sibling("Currency"); ok && currency == "JPY" {
+				return 1000, 1000, true
+			}
+			return 0, 0, false
+		}),
+	)
+	assert.Nil(t, err)
+
+	var q Quote
+	assert.Nil(t, g.Fill(&q))
+	if assert.NotNil(t, q.Price) {
+		assert.Equal(t, 1000, *q.Price)
+	}
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Set sets the value at a path, as returned by Matcher.Path, such as Address.Country or Orders[*].Status, where '*'
+// matches any sequence of characters. The value must be assignable or convertible to the type at the path, or to the
+// type it points to; nil sets the zero value. Values within it are not generated. Pointers on the way to a path
+// which the pattern may match, such as Address in Address.Country, are never left nil.
+func Set(path string, value any) Option {
+	return func(g *generator) (*generator, error) {
+		source := fmt.Sprintf("Set(%s)", path)
+		rvalue := reflect.ValueOf(value)
+		addRule(g, &g.overrides, fieldLevel, source, func(t *Matcher) (reflect.Value, bool, error) {
+			return rvalue, pathMatches(path, t.Path()), nil
+		})
+		g.setPaths = append(append([]string(nil), g.setPaths...), path)
+		return g, nil
+	}
+}
+
+// onSetPath reports whether a path lies on the way to a path which a Set pattern may match.
+func (g *generator) onSetPath(path string) bool {
+	for _, pattern := range g.setPaths {
+		if pathLeadsTo(pattern, path+".") || pathLeadsTo(pattern, path+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// setsPath reports whether a Set pattern may match a path or a path below it.
+func (g *generator) setsPath(path string) bool {
+	for _, pattern := range g.setPaths {
+		if pathMatches(pattern, path) {
+			return true
+		}
+	}
+	return g.onSetPath(path)
+}
+
+// pathLeadsTo reports whether some path beginning with prefix matches pattern.
+func pathLeadsTo(pattern, prefix string) bool {
+	literal, _, wild := strings.Cut(pattern, "*")
+	if len(prefix) <= len(literal) {
+		return strings.HasPrefix(literal, prefix)
+	}
+	// a '*' after the leading literal matches whatever remains of the prefix
+	return wild && strings.HasPrefix(prefix, literal)
+}
+
+// setOverride sets value from the first matching Set rule, if any, reporting whether it did so.
+func (g *generator) setOverride(value reflect.Value, matcher *Matcher) (bool, error) {
+	if len(g.overrides) == 0 {
+		return false, nil
+	}
+	rtype := value.Type()
+	matcher = matcher.forSimpleType(rtype)
+	override, source, ok, err := g.overrides.find(g, matcher, nil)
+	if err != nil || !ok {
+		return false, err
+	}
+	switch {
+	case !override.IsValid():
+		value.Set(reflect.Zero(rtype))
+	case override.Type().AssignableTo(rtype):
+		value.Set(override)
+	case override.CanConvert(rtype) && (rtype.Kind() != reflect.String || override.Kind() == reflect.String):
+		value.Set(override.Convert(rtype))
+	case rtype.Kind() == reflect.Pointer && override.Type().AssignableTo(rtype.Elem()):
+		value.Set(reflect.New(rtype.Elem()))
+		value.Elem().Set(override)
+	default:
+		return false, fmt.Errorf("%s: cannot set %s to a value of type %s", source, rtype, override.Type())
+	}
+	return true, g.trace(matcher, source)
+}
+
+// WithTrait defines a named trait, which applies options when selected with Trait.
+func WithTrait(name string, options ...Option) Option {
+	return func(g *generator) (*generator, error) {
+		traits := make(map[string][]Option, len(g.traits)+1)
+		for key, value := range g.traits {
+			traits[key] = value
+		}
+		traits[name] = options
+		g.traits = traits
+		return g, nil
+	}
+}
+
+// Trait applies the options of a trait defined with WithTrait.
+func Trait(name string) Option {
+	return func(g *generator) (*generator, error) {
+		options, ok := g.traits[name]
+		if !ok {
+			return nil, fmt.Errorf("Trait: unknown trait %q", name)
+		}
+		return g.apply(options...)
+	}
+}
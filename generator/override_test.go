@@ -0,0 +1,75 @@
+package generator_test
+
+import (
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type Member struct {
+	Name    string
+	Role    string
+	Level   int8
+	Country string
+	Address Address
+	Groups  []Address
+}
+
+func TestFillOptions(t *testing.T) {
+	base, err := generator.New().WithOptions(
+		generator.WithTrait("admin", generator.Set("Role", "admin"), generator.Set("Level", 9)),
+		generator.WithTrait("german", generator.Set("Country", "DE"), generator.Set("Address.Street", "Hauptstraße")),
+	)
+	assert.Nil(t, err)
+
+	m := new(Member)
+	assert.Nil(t, base.Fill(m, generator.Trait("admin"), generator.Trait("german")))
+	assert.Equal(t, "admin", m.Role)
+	assert.Equal(t, int8(9), m.Level)
+	assert.Equal(t, "DE", m.Country)
+	assert.Equal(t, "Hauptstraße", m.Address.Street)
+
+	assert.Nil(t, base.Fill(m, generator.Set("Groups[*].Number", 5), generator.Set("Address", Address{Street: "High Street"})))
+	for _, group := range m.Groups {
+		assert.Equal(t, 5, group.Number)
+	}
+	assert.Equal(t, Address{Street: "High Street"}, m.Address)
+
+	// call-scoped options do not persist
+	assert.Nil(t, base.Fill(m))
+	assert.NotEqual(t, "admin", m.Role)
+	assert.NotEqual(t, "High Street", m.Address.Street)
+}
+
+func TestFillOptionErrors(t *testing.T) {
+	g := generator.New()
+	assert.NotNil(t, g.Fill(new(Member), generator.Trait("unknown")))
+	assert.NotNil(t, g.Fill(new(Member), generator.Set("Level", "high")))
+	assert.NotNil(t, g.Fill(new(Member), generator.Set("Name", 65)))
+}
+
+type Household struct {
+	Home    *Address
+	Holiday *Address
+	Others  []*Address
+}
+
+func TestSetUnderPointer(t *testing.T) {
+	g, err := generator.New().WithOptions(generator.WithPointerNilRatio(1))
+	assert.Nil(t, err)
+
+	for i := 0; i < 10; i++ {
+		h := new(Household)
+		assert.Nil(t, g.Fill(h, generator.Set("Home.Street", "Station Road"), generator.Set("Others[*].Number", 7)))
+		if assert.NotNil(t, h.Home) {
+			assert.Equal(t, "Station Road", h.Home.Street)
+		}
+		assert.Nil(t, h.Holiday)
+		for _, other := range h.Others {
+			if assert.NotNil(t, other) {
+				assert.Equal(t, 7, other.Number)
+			}
+		}
+	}
+}
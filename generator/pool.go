@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Pool holds entities recorded by a generator, so that later values can refer to them. It lives across calls to Fill,
+// and is shared by clones of the generator.
+type Pool struct {
+	mu       sync.Mutex
+	entities map[reflect.Type][]reflect.Value
+}
+
+func newPool() *Pool {
+	return &Pool{entities: make(map[reflect.Type][]reflect.Value)}
+}
+
+// Add records values as entities of their types.
+func (p *Pool) Add(values ...any) {
+	for _, value := range values {
+		p.add(reflect.ValueOf(value))
+	}
+}
+
+func (p *Pool) add(value reflect.Value) reflect.Value {
+	entity := reflect.New(value.Type()).Elem()
+	entity.Set(value)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entities[value.Type()] = append(p.entities[value.Type()], entity)
+	return entity
+}
+
+// remove discards an entity returned by add.
+func (p *Pool) remove(entity reflect.Value) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entities := p.entities[entity.Type()]
+	for i := len(entities) - 1; i >= 0; i-- {
+		if entities[i].UnsafeAddr() == entity.UnsafeAddr() {
+			p.entities[entity.Type()] = append(entities[:i:i], entities[i+1:]...)
+			return
+		}
+	}
+}
+
+// Len returns the number of entities of type rtype recorded.
+func (p *Pool) Len(rtype reflect.Type) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entities[rtype])
+}
+
+// Reset discards every recorded entity.
+func (p *Pool) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entities = make(map[reflect.Type][]reflect.Value)
+}
+
+func (p *Pool) pick(r Inclusive, rtype reflect.Type) (reflect.Value, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entities := p.entities[rtype]
+	if len(entities) == 0 {
+		return reflect.Value{}, false
+	}
+	return entities[r.InclusiveUint64n(0, uint64(len(entities)-1))], true
+}
+
+// Pick returns a randomly chosen entity of type T from the pool of g, reporting whether there was one.
+func Pick[T any](g Generator) (T, bool) {
+	var entity T
+	picked, ok := g.Pool().pick(g, reflect.TypeOf(&entity).Elem())
+	if ok {
+		entity = picked.Interface().(T)
+	}
+	return entity, ok
+}
+
+// WithEntity records every value of type T filled by the generator in its pool.
+func WithEntity[T any]() Option {
+	return func(g *generator) (*generator, error) {
+		g.addEntityType(reflect.TypeOf((*T)(nil)).Elem())
+		return g, nil
+	}
+}
+
+func (g *generator) addEntityType(rtype reflect.Type) {
+	entityTypes := make(map[reflect.Type]bool, len(g.entityTypes)+1)
+	for key := range g.entityTypes {
+		entityTypes[key] = true
+	}
+	entityTypes[rtype] = true
+	g.entityTypes = entityTypes
+}
+
+// WithReference draws the values of the from field from the key field of entities of type E recorded in the pool,
+// so that generated foreign keys refer to generated entities, as in
+//
+//	generator.WithReference(
+//		generator.Field(func(o *Order) *int { return &o.UserID }),
+//		generator.Field(func(u *User) *int { return &u.ID }),
+//	)
+//
+// Entities of type E are recorded as by WithEntity. While there are none, values are generated as usual.
+func WithReference[T, E, F any](from *FieldRule[T, F], key *FieldRule[E, F]) Option {
+	return func(g *generator) (*generator, error) {
+		if from.err != nil {
+			return nil, from.err
+		}
+		if key.err != nil {
+			return nil, key.err
+		}
+		entityType := reflect.TypeOf((*E)(nil)).Elem()
+		g.addEntityType(entityType)
+		p := from.Predicate()
+		source := fmt.Sprintf("WithReference(%s, %s)", from, key)
+		addGeneratingRule(g, &g.overrides, fieldLevel, source, func(g *generator, t *Matcher) (reflect.Value, bool, error) {
+			if !p(t) {
+				return reflect.Value{}, false, nil
+			}
+			entity, ok := g.pool.pick(g, entityType)
+			if !ok {
+				return reflect.Value{}, false, nil
+			}
+			for _, step := range key.path {
+				entity = entity.FieldByName(step.name)
+			}
+			return entity, true, nil
+		})
+		return g, nil
+	}
+}
+
+// Pool returns the pool of entities recorded by the generator.
+func (g *generator) Pool() *Pool {
+	return g.pool
+}
+
+func (g *generator) record(value reflect.Value) {
+	if g.entityTypes[value.Type()] && value.CanInterface() {
+		entity := g.pool.add(value)
+		g.onReject(func() {
+			g.pool.remove(entity)
+		})
+	}
+}
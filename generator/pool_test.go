@@ -0,0 +1,68 @@
+package generator_test
+
+import (
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type Customer struct {
+	ID   int
+	Name string
+}
+
+type Purchase struct {
+	ID         int
+	CustomerID int
+}
+
+func TestPool(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithIntRange(1000, 1000000),
+		generator.WithReference(
+			generator.Field(func(p *Purchase) *int { return &p.CustomerID }),
+			generator.Field(func(c *Customer) *int { return &c.ID }),
+		),
+	)
+	assert.Nil(t, err)
+
+	_, ok := generator.Pick[Customer](g)
+	assert.False(t, ok)
+
+	customers, err := generator.MakeN[Customer](g, 3)
+	assert.Nil(t, err)
+	ids := map[int]bool{}
+	for _, c := range customers {
+		ids[c.ID] = true
+	}
+
+	purchases, err := generator.MakeN[Purchase](g, 10, generator.WithStringLengthRange(1, 1))
+	assert.Nil(t, err)
+	for _, p := range purchases {
+		assert.True(t, ids[p.CustomerID])
+	}
+
+	picked, ok := generator.Pick[Customer](g)
+	assert.True(t, ok)
+	assert.Contains(t, customers, picked)
+
+	derived, _ := g.Derive()
+	assert.Same(t, g.Pool(), derived.Pool())
+
+	g.Pool().Reset()
+	g.Pool().Add(Customer{ID: 7})
+	var p Purchase
+	assert.Nil(t, g.Fill(&p))
+	assert.Equal(t, 7, p.CustomerID)
+}
+
+func TestEntity(t *testing.T) {
+	g, err := generator.New().WithOptions(generator.WithEntity[Address]())
+	assert.Nil(t, err)
+	u := new(User)
+	assert.Nil(t, g.Fill(u, generator.WithPointerNilRatio(0)))
+	picked, ok := generator.Pick[Address](g)
+	assert.True(t, ok)
+	assert.Contains(t, []Address{u.Address, *u.Other}, picked)
+}
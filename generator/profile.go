@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+)
+
+// Profile names a built-in set of length, range and nil ratio settings.
+type Profile string
+
+const (
+	// Tiny generates short strings, slices and maps, and small numbers.
+	Tiny Profile = "tiny"
+	// Default restores the settings used when no options are given.
+	Default Profile = "default"
+	// Large generates long strings, slices and maps, and numbers with 32-bit ranges.
+	Large Profile = "large"
+	// Stress generates very long strings, slices and maps, including empty ones, and numbers across the full range
+	// of each kind.
+	Stress Profile = "stress"
+)
+
+// WithProfile applies the settings of a built-in profile. Options applied later override them.
+func WithProfile(p Profile) Option {
+	return func(g *generator) (*generator, error) {
+		options, ok := profiles()[p]
+		if !ok {
+			return nil, fmt.Errorf("WithProfile: unknown profile %q", p)
+		}
+		return g.apply(options...)
+	}
+}
+
+func profiles() map[Profile][]Option {
+	return map[Profile][]Option{
+		Tiny: {
+			WithStringLengthRange(0, 4),
+			WithSliceLengthRange(0, 2),
+			WithMapLengthRange(0, 2),
+			withIntegerRanges(9),
+			WithFloat32Range(0, 9),
+			WithFloat64Range(0, 9),
+			WithPointerNilRatio(defNilPointerRatio),
+		},
+		Default: {
+			WithStringLengthRange(defMinStrLen, defMaxStrLen),
+			WithSliceLengthRange(defMinSliceLen, defMaxSliceLen),
+			WithMapLengthRange(defMinMapLen, defMaxMapLen),
+			withIntegerRanges(int32(defMaxInt)),
+			WithFloat32Range(0, float32(defMaxFloat)),
+			WithFloat64Range(0, defMaxFloat),
+			WithPointerNilRatio(defNilPointerRatio),
+		},
+		Large: {
+			WithStringLengthRange(16, 256),
+			WithSliceLengthRange(16, 128),
+			WithMapLengthRange(16, 128),
+			withIntegerRanges(math.MaxInt32),
+			WithFloat32Range(-math.MaxInt32, math.MaxInt32),
+			WithFloat64Range(-math.MaxInt32, math.MaxInt32),
+			WithPointerNilRatio(0.1),
+		},
+		Stress: {
+			WithStringLengthRange(0, 4096),
+			WithSliceLengthRange(0, 1024),
+			WithMapLengthRange(0, 1024),
+			WithIntRange(math.MinInt, math.MaxInt),
+			WithInt8Range(math.MinInt8, math.MaxInt8),
+			WithInt16Range(math.MinInt16, math.MaxInt16),
+			WithInt32Range(math.MinInt32, math.MaxInt32),
+			WithInt64Range(math.MinInt64, math.MaxInt64),
+			WithUintRange(0, math.MaxUint),
+			WithUint8Range(0, math.MaxUint8),
+			WithUint16Range(0, math.MaxUint16),
+			WithUint32Range(0, math.MaxUint32),
+			WithUint64Range(0, math.MaxUint64),
+			WithFloat32Range(-math.MaxFloat32, math.MaxFloat32),
+			WithFloat64Range(-math.MaxFloat64, math.MaxFloat64),
+			WithPointerNilRatio(defNilPointerRatio),
+		},
+	}
+}
+
+// withIntegerRanges sets the range of every integer kind to [0, max], capped to the largest value of the kind.
+func withIntegerRanges(max int32) Option {
+	return func(g *generator) (*generator, error) {
+		return g.apply(
+			WithIntRange(0, int(max)),
+			WithInt8Range(0, int8(minInt32(max, math.MaxInt8))),
+			WithInt16Range(0, int16(minInt32(max, math.MaxInt16))),
+			WithInt32Range(0, max),
+			WithInt64Range(0, int64(max)),
+			WithUintRange(0, uint(max)),
+			WithUint8Range(0, uint8(minInt32(max, math.MaxUint8))),
+			WithUint16Range(0, uint16(minInt32(max, math.MaxUint16))),
+			WithUint32Range(0, uint32(max)),
+			WithUint64Range(0, uint64(max)),
+		)
+	}
+}
+
+func minInt32(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
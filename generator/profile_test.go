@@ -0,0 +1,96 @@
+package generator_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDerive(t *testing.T) {
+	base, err := generator.New().WithOptions(generator.WithIntRange(1, 1))
+	assert.Nil(t, err)
+
+	derived, err := base.Derive(generator.WithIntRange(2, 2))
+	assert.Nil(t, err)
+	clone := base.Clone()
+	_, err = clone.WithOptions(generator.WithIntRange(3, 3))
+	assert.Nil(t, err)
+
+	var fromBase, fromDerived, fromClone int
+	assert.Nil(t, base.Fill(&fromBase))
+	assert.Nil(t, derived.Fill(&fromDerived))
+	assert.Nil(t, clone.Fill(&fromClone))
+	assert.Equal(t, 1, fromBase)
+	assert.Equal(t, 2, fromDerived)
+	assert.Equal(t, 3, fromClone)
+	assert.Equal(t, generator.Range{Min: "1", Max: "1"}, base.Config().Ranges["int"])
+}
+
+func TestProfiles(t *testing.T) {
+	tiny, err := generator.New().WithOptions(generator.WithProfile(generator.Tiny))
+	assert.Nil(t, err)
+	for i := 0; i < 20; i++ {
+		u := new(Unmatched)
+		assert.Nil(t, tiny.Fill(u))
+		assert.LessOrEqual(t, len(u.String), 4)
+		assert.LessOrEqual(t, len(u.Slice), 2)
+		assert.LessOrEqual(t, u.Int, 9)
+		assert.LessOrEqual(t, u.Uint8, uint8(9))
+	}
+
+	stress, err := generator.New().WithOptions(generator.WithProfile(generator.Stress))
+	assert.Nil(t, err)
+	large := false
+	for i := 0; i < 20; i++ {
+		var u uint64
+		assert.Nil(t, stress.Fill(&u))
+		large = large || u > math.MaxUint32
+	}
+	assert.True(t, large)
+
+	_, err = generator.New().WithOptions(generator.WithProfile("huge"))
+	assert.NotNil(t, err)
+}
+
+func TestProfileRoundTrip(t *testing.T) {
+	for _, p := range []generator.Profile{generator.Tiny, generator.Default, generator.Large, generator.Stress} {
+		p := p
+		t.Run(string(p), func(tt *testing.T) {
+			original, err := generator.New().WithOptions(generator.WithSeed(7), generator.WithProfile(p))
+			assert.Nil(tt, err)
+			exported := original.Config()
+
+			asJSON, err := json.Marshal(exported)
+			assert.Nil(tt, err)
+			asYAML, err := yaml.Marshal(exported)
+			assert.Nil(tt, err)
+			for _, document := range [][]byte{asJSON, asYAML} {
+				options, err := generator.LoadConfig(bytes.NewReader(document))
+				assert.Nil(tt, err)
+				reloaded, err := generator.New().WithOptions(options...)
+				assert.Nil(tt, err)
+				assert.Equal(tt, exported, reloaded.Config())
+
+				fresh, _ := generator.New().WithOptions(generator.WithSeed(7), generator.WithProfile(p))
+				for i := 0; i < 5; i++ {
+					first, second := new(Unmatched), new(Unmatched)
+					assert.Nil(tt, fresh.Fill(first))
+					assert.Nil(tt, reloaded.Fill(second))
+					assert.Equal(tt, first, second)
+				}
+			}
+		})
+	}
+
+	stress, err := generator.New().WithOptions(generator.WithProfile(generator.Stress))
+	assert.Nil(t, err)
+	asJSON, err := json.Marshal(stress.Config())
+	assert.Nil(t, err)
+	assert.Contains(t, string(asJSON), `"int64":{"min":-9223372036854775808,"max":9223372036854775807}`)
+	assert.Contains(t, string(asJSON), `"uint64":{"min":0,"max":18446744073709551615}`)
+}
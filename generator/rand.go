@@ -18,9 +18,10 @@ type Randomiser interface {
 	Float64() float64
 }
 
-var _ inclusive = (*generator)(nil)
+var _ Inclusive = (*generator)(nil)
 
-type inclusive interface {
+// Inclusive defines random methods over closed intervals.
+type Inclusive interface {
 	InclusiveInt32n(min, max int32) int32
 	InclusiveUint32n(min, max uint32) uint32
 	InclusiveInt64n(min, max int64) int64
@@ -43,7 +44,7 @@ func (g *generator) InclusiveUint32n(min, max uint32) uint32 {
 	if min == 0 && max == math.MaxUint32 {
 		return g.Uint32()
 	}
-	return g.Uint32n(max-min) + min
+	return g.Uint32n(max-min+1) + min
 }
 
 // InclusiveInt64n returns a random int64 in the closed interval [min, max].
@@ -62,7 +63,7 @@ func (g *generator) InclusiveUint64n(min, max uint64) uint64 {
 	if min == 0 && max == math.MaxUint64 {
 		return g.Uint64()
 	}
-	return g.Uint64n(max-min) + min
+	return g.Uint64n(max-min+1) + min
 }
 
 // Uint32 returns a uniformly distributed random 32-bit value as an uint32.
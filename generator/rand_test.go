@@ -54,3 +54,14 @@ func TestGeneratorInclusiveUint64n(t *testing.T) {
 	expected := out >= 0 && out <= math.MaxUint64
 	assert.True(t, expected)
 }
+
+func TestGeneratorInclusiveBounds(t *testing.T) {
+
+	subject := generator.New()
+
+	seen := map[uint32]bool{}
+	for i := 0; i < 200; i++ {
+		seen[subject.InclusiveUint32n(1, 2)] = true
+	}
+	assert.Equal(t, map[uint32]bool{1: true, 2: true}, seen)
+}
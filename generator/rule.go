@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"fmt"
+)
+
+// Rules registered by options are consulted in order of precedence, the same way for every kind of value:
+//
+//   - a rule with a higher priority, set with Priority, takes precedence over one with a lower priority;
+//   - otherwise a more specific rule takes precedence over a less specific one, where settings for a whole kind, such
+//     as WithIntRange or WithRunes, are least specific, followed by WithRange for a named type, then rules with a
+//     Matcher callback or a Predicate, and finally rules for a field selected with Field;
+//   - otherwise the rule registered last takes precedence.
+//
+// The first matching rule in that order produces the value. WithTrace reports which rule that was.
+type specificity int
+
+const (
+	kindLevel specificity = iota
+	typeLevel
+	matchLevel
+	fieldLevel
+)
+
+const defaultSource = "default"
+
+type rule[V any] struct {
+	fn          func(g *generator, t *Matcher) (V, bool, error)
+	priority    int
+	specificity specificity
+	seq         int
+	source      string
+}
+
+func (r rule[V]) precedes(other rule[V]) bool {
+	if r.priority != other.priority {
+		return r.priority > other.priority
+	}
+	if r.specificity != other.specificity {
+		return r.specificity > other.specificity
+	}
+	return r.seq > other.seq
+}
+
+// ruleset holds rules in order of precedence. It is never modified in place, so it may be shared between clones.
+type ruleset[V any] []rule[V]
+
+// find returns the output of the first matching rule. An error returned by the rule, a panic raised by it or a
+// failure of validate on its output is returned annotated with the rule and the path of the matched value.
+func (rs ruleset[V]) find(g *generator, t *Matcher, validate func(V) error) (V, string, bool, error) {
+	for _, r := range rs {
+		out, ok, err := r.call(g, t)
+		if err == nil && ok && validate != nil {
+			err = validate(out)
+		}
+		if err != nil {
+			return out, r.source, false, fmt.Errorf("%s at %q: %w", r.source, t.Path(), err)
+		}
+		if ok {
+			return out, r.source, true, nil
+		}
+	}
+	var none V
+	return none, "", false, nil
+}
+
+func (r rule[V]) call(g *generator, t *Matcher) (out V, ok bool, err error) {
+	err = recovered(func() error {
+		out, ok, err = r.fn(g, t)
+		return err
+	})
+	return out, ok, err
+}
+
+// recovered calls fn, returning a panic raised by it as an error.
+func recovered(fn func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return fn()
+}
+
+func addRule[V any](g *generator, rs *ruleset[V], spec specificity, source string, fn func(t *Matcher) (V, bool, error)) {
+	addGeneratingRule(g, rs, spec, source, func(_ *generator, t *Matcher) (V, bool, error) {
+		return fn(t)
+	})
+}
+
+// addGeneratingRule adds a rule whose function is passed the generator in use, which may be a clone of g.
+func addGeneratingRule[V any](g *generator, rs *ruleset[V], spec specificity, source string, fn func(g *generator, t *Matcher) (V, bool, error)) {
+	g.seq++
+	r := rule[V]{
+		fn:          fn,
+		priority:    g.priority,
+		specificity: spec,
+		seq:         g.seq,
+		source:      fmt.Sprintf("%s #%d", source, g.seq),
+	}
+	next := make(ruleset[V], 0, len(*rs)+1)
+	added := false
+	for _, existing := range *rs {
+		if !added && r.precedes(existing) {
+			next = append(next, r)
+			added = true
+		}
+		next = append(next, existing)
+	}
+	if !added {
+		next = append(next, r)
+	}
+	*rs = next
+}
+
+// trace calls the function registered with WithTrace, if any, returning a panic raised by it as an error.
+func (g *generator) trace(t *Matcher, source string) error {
+	if g.tracer == nil {
+		return nil
+	}
+	if err := recovered(func() error { g.tracer(t, source); return nil }); err != nil {
+		return fmt.Errorf("WithTrace at %q: %w", t.Path(), err)
+	}
+	return nil
+}
+
+// Priority applies options so that the rules they register take precedence over any rule with a lower priority,
+// whatever its specificity. Rules registered outside Priority have priority 0.
+func Priority(n int, options ...Option) Option {
+	return func(g *generator) (*generator, error) {
+		previous := g.priority
+		g.priority = n
+		defer func() {
+			g.priority = previous
+		}()
+		return g.apply(options...)
+	}
+}
+
+// WithTrace registers a function which is called with the Matcher and the source of the rule used for each value
+// generated, or "default" where no rule matched. Strings report both their length and rune sources. A panic raised
+// by fn stops the fill and is returned by Fill as an error.
+func WithTrace(fn func(t *Matcher, source string)) Option {
+	return func(g *generator) (*generator, error) {
+		g.tracer = fn
+		return g, nil
+	}
+}
@@ -0,0 +1,89 @@
+package generator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/merlincox/reflective/generator/match"
+	"github.com/stretchr/testify/assert"
+)
+
+type Ranked struct {
+	First  string
+	Second string
+	Count  int
+	Total  int
+	Items  []Address
+	Lookup map[string]bool
+}
+
+func TestPrecedence(t *testing.T) {
+	subject := generator.New()
+	subject, err := subject.WithOptions(
+		generator.Field(func(r *Ranked) *int { return &r.Count }).Range(1, 1),
+		generator.WithIntFn(func(m *generator.Matcher) (int, int, bool) {
+			return 2, 2, true
+		}),
+		generator.WithIntRange(3, 3),
+		generator.WithStringFn(func(m *generator.Matcher) (string, bool) {
+			return "first", true
+		}),
+		generator.WithStringFn(func(m *generator.Matcher) (string, bool) {
+			return "last", m.FieldName() == "First"
+		}),
+		generator.Priority(1,
+			generator.WithStringWhen(match.FieldOf[Ranked]("Second"), "priority"),
+			generator.WithIntRangeWhen(match.FieldOf[Ranked]("Total"), 4, 4),
+		),
+	)
+	assert.Nil(t, err)
+
+	r := new(Ranked)
+	assert.Nil(t, subject.Fill(r))
+	assert.Equal(t, "last", r.First)
+	assert.Equal(t, "priority", r.Second)
+	assert.Equal(t, 1, r.Count)
+	assert.Equal(t, 4, r.Total)
+	for _, item := range r.Items {
+		assert.Equal(t, 2, item.Number)
+		assert.Equal(t, "first", item.Street)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	sources := map[string]string{}
+	subject := generator.New()
+	subject, err := subject.WithOptions(
+		generator.WithSliceLengthRange(1, 1),
+		generator.WithMapLengthRange(1, 1),
+		generator.WithIntRangeWhen(match.FieldOf[Address]("Number"), 5, 5),
+		generator.WithTrace(func(m *generator.Matcher, source string) {
+			sources[m.Path()] = source
+		}),
+	)
+	assert.Nil(t, err)
+
+	assert.Nil(t, subject.Fill(new(Ranked)))
+	assert.Equal(t, "default", sources["Count"])
+	assert.Equal(t, "length: default, runes: default", sources["First"])
+	assert.Equal(t, "WithSliceLengthRange #1", sources["Items#len"])
+	assert.Equal(t, "WithMapLengthRange #2", sources["Lookup#len"])
+	assert.Equal(t, "WithRangeWhen[int] #3", sources["Items[0].Number"])
+	found := false
+	for path := range sources {
+		if strings.HasPrefix(path, "Lookup[") {
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	subject, err = generator.New().WithOptions(generator.WithTrace(func(m *generator.Matcher, source string) {
+		panic("tracer")
+	}))
+	assert.Nil(t, err)
+	err = subject.Fill(new(Ranked))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "WithTrace at")
+	assert.Contains(t, err.Error(), "panic: tracer")
+}
@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"database/sql"
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// Some types keep their state private and can only be filled through their own API, which keeps their invariants.
+// Values of such a type are filled from registered samples, through encoding.TextUnmarshaler or sql.Scanner, or else
+// through a Store or Set method taking a single argument, as provided by the types of sync/atomic and by types
+// registered with WithSetter.
+
+// WithSetter fills values of the struct type T, which must have no exported fields, by calling its Store or Set
+// method, taking a single argument of another type, with a generated argument. The types of sync/atomic are filled
+// so without it.
+func WithSetter[T any]() Option {
+	return func(g *generator) (*generator, error) {
+		rtype := reflect.TypeOf((*T)(nil)).Elem()
+		if _, ok := setterOf(reflect.New(rtype).Elem()); !ok {
+			return nil, fmt.Errorf("WithSetter[%s]: no Store or Set method of a struct with no exported fields", rtype)
+		}
+		setters := make(map[reflect.Type]bool, len(g.setters)+1)
+		for key := range g.setters {
+			setters[key] = true
+		}
+		setters[rtype] = true
+		g.setters = setters
+		return g, nil
+	}
+}
+
+// WithTextSample fills values of type T, which must implement encoding.TextUnmarshaler through a pointer, by
+// unmarshalling text returned by fn.
+func WithTextSample[T any](fn func(r Randomiser) (string, error)) Option {
+	return sampleRule[T]("WithTextSample", func(g *generator, p *T) error {
+		text, err := fn(g)
+		if err != nil {
+			return err
+		}
+		return any(p).(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	}, func(p *T) bool {
+		_, ok := any(p).(encoding.TextUnmarshaler)
+		return ok
+	})
+}
+
+// WithScanSample fills values of type T, which must implement sql.Scanner through a pointer, by scanning a source
+// value returned by fn, such as an int64, float64, bool, []byte, string or time.Time.
+func WithScanSample[T any](fn func(r Randomiser) (any, error)) Option {
+	return sampleRule[T]("WithScanSample", func(g *generator, p *T) error {
+		src, err := fn(g)
+		if err != nil {
+			return err
+		}
+		return any(p).(sql.Scanner).Scan(src)
+	}, func(p *T) bool {
+		_, ok := any(p).(sql.Scanner)
+		return ok
+	})
+}
+
+func sampleRule[T any](name string, set func(g *generator, p *T) error, implements func(p *T) bool) Option {
+	return func(g *generator) (*generator, error) {
+		rtype := reflect.TypeOf((*T)(nil)).Elem()
+		source := fmt.Sprintf("%s[%s]", name, rtype)
+		if !implements(new(T)) {
+			return nil, fmt.Errorf("%s: %s does not implement the required interface", source, reflect.PointerTo(rtype))
+		}
+		addGeneratingRule(g, &g.samples, typeLevel, source, func(g *generator, t *Matcher) (reflect.Value, bool, error) {
+			if t.rtype != rtype {
+				return reflect.Value{}, false, nil
+			}
+			p := new(T)
+			return reflect.ValueOf(p).Elem(), true, set(g, p)
+		})
+		return g, nil
+	}
+}
+
+// fillBySample fills a value from a sample registered for its type, if any, reporting whether it did so.
+func (g *generator) fillBySample(value reflect.Value, matcher *Matcher) (bool, error) {
+	if len(g.samples) == 0 {
+		return false, nil
+	}
+	t := matcher.forSimpleType(value.Type())
+	sample, source, ok, err := g.samples.find(g, t, nil)
+	if err != nil || !ok {
+		return ok, err
+	}
+	value.Set(sample)
+	return true, g.trace(t, source)
+}
+
+// fillBySetter fills a value of a type of sync/atomic or registered with WithSetter through its setter method,
+// reporting whether it did so. A panic raised by the method is returned as an error.
+func (g *generator) fillBySetter(value reflect.Value, matcher *Matcher) (bool, error) {
+	if rtype := value.Type(); rtype.PkgPath() != "sync/atomic" && !g.setters[rtype] {
+		return false, nil
+	}
+	setter, ok := setterOf(value)
+	if !ok {
+		return false, nil
+	}
+	arg := reflect.New(setter.Type().In(0)).Elem()
+	t := matcher.forSimpleType(value.Type())
+	if err := g.fill(arg, t); err != nil {
+		return true, err
+	}
+	if err := recovered(func() error { setter.Call([]reflect.Value{arg}); return nil }); err != nil {
+		return true, fmt.Errorf("%s setter at %q: %w", value.Type(), t.Path(), err)
+	}
+	return true, nil
+}
+
+// setterOf returns the Store or Set method of a struct value with no exported fields, where the method takes a single
+// argument which fill can generate and which is not of the struct type itself.
+func setterOf(value reflect.Value) (reflect.Value, bool) {
+	rtype := value.Type()
+	if rtype.Kind() != reflect.Struct || !value.CanAddr() || hasExportedFields(rtype) {
+		return reflect.Value{}, false
+	}
+	for _, name := range []string{"Store", "Set"} {
+		method := value.Addr().MethodByName(name)
+		if !method.IsValid() || method.Type().NumIn() != 1 || method.Type().NumOut() != 0 {
+			continue
+		}
+		if in := method.Type().In(0); in.Kind() != reflect.Interface && indirect(in) != rtype {
+			return method, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func hasExportedFields(rtype reflect.Type) bool {
+	for i := 0; i < rtype.NumField(); i++ {
+		if rtype.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
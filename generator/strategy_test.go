@@ -0,0 +1,139 @@
+package generator_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type Percent struct {
+	value int
+}
+
+func (p *Percent) Set(value int) {
+	p.value = value % 101
+}
+
+type Brittle struct {
+	value int
+}
+
+func (b *Brittle) Set(value int) {
+	panic("brittle")
+}
+
+type BrittleHolder struct {
+	Part Brittle
+}
+
+type Sampled struct {
+	Amount big.Int
+}
+
+type Stateful struct {
+	Hits    atomic.Int64
+	Ready   atomic.Bool
+	Home    atomic.Pointer[Address]
+	Share   Percent
+	Remote  netip.Addr
+	Comment sql.NullString
+}
+
+func TestStrategies(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithPointerNilRatio(0),
+		generator.WithBoolTrueRatio(1),
+		generator.WithRange[int64](500, 1000),
+		generator.WithRange(1011, 1020),
+		generator.WithSetter[Percent](),
+		generator.WithTextSample[netip.Addr](func(r generator.Randomiser) (string, error) {
+			return fmt.Sprintf("10.0.0.%d", r.Uint32n(256)), nil
+		}),
+		generator.WithScanSample[sql.NullString](func(r generator.Randomiser) (any, error) {
+			return "scanned", nil
+		}),
+	)
+	assert.Nil(t, err)
+
+	var s Stateful
+	assert.Nil(t, g.Fill(&s))
+	assert.GreaterOrEqual(t, s.Hits.Load(), int64(500))
+	assert.True(t, s.Ready.Load())
+	assert.NotNil(t, s.Home.Load())
+	assert.NotZero(t, s.Share.value)
+	assert.LessOrEqual(t, s.Share.value, 10)
+	assert.True(t, s.Remote.Is4())
+	assert.Equal(t, sql.NullString{String: "scanned", Valid: true}, s.Comment)
+
+	_, err = generator.New().WithOptions(generator.WithTextSample[Address](func(r generator.Randomiser) (string, error) {
+		return "", nil
+	}))
+	assert.NotNil(t, err)
+	_, err = generator.New().WithOptions(generator.WithSetter[Address]())
+	assert.NotNil(t, err)
+
+	// other types with a setter method are not filled through it unless registered
+	var unregistered Stateful
+	assert.Nil(t, generator.New().Fill(&unregistered))
+	assert.Zero(t, unregistered.Share.value)
+
+	failure := errors.New("bad sample")
+	g, err = generator.New().WithOptions(generator.WithTextSample[netip.Addr](func(r generator.Randomiser) (string, error) {
+		return "", failure
+	}))
+	assert.Nil(t, err)
+	err = g.Fill(&s)
+	assert.ErrorIs(t, err, failure)
+	assert.Contains(t, err.Error(), `WithTextSample[netip.Addr] #1 at "Remote"`)
+}
+
+func TestSampleChecks(t *testing.T) {
+	sample := generator.WithTextSample[big.Int](func(r generator.Randomiser) (string, error) {
+		return "42", nil
+	})
+	rejected := errors.New("rejected")
+	g, err := generator.New().WithOptions(
+		sample,
+		generator.WithMaxAttempts(3),
+		generator.WithValidator(func(v big.Int) error { return rejected }),
+	)
+	assert.Nil(t, err)
+	assert.ErrorIs(t, g.Fill(new(Sampled)), rejected)
+
+	g, err = generator.New().WithOptions(sample, generator.WithZeroRatio(1))
+	assert.Nil(t, err)
+	s := new(Sampled)
+	assert.Nil(t, g.Fill(s))
+	assert.Zero(t, s.Amount.Sign())
+
+	g, err = generator.New().WithOptions(sample, generator.WithEntity[big.Int]())
+	assert.Nil(t, err)
+	assert.Nil(t, g.Fill(s))
+	assert.Equal(t, int64(42), s.Amount.Int64())
+	picked, ok := generator.Pick[big.Int](g)
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), picked.Int64())
+
+	g, err = generator.New().WithOptions(sample, generator.WithAfterFill(func(p *big.Int, r generator.Randomiser) error {
+		p.Add(p, big.NewInt(1))
+		return nil
+	}))
+	assert.Nil(t, err)
+	assert.Nil(t, g.Fill(s))
+	assert.Equal(t, int64(43), s.Amount.Int64())
+}
+
+func TestSetterPanics(t *testing.T) {
+	g, err := generator.New().WithOptions(generator.WithSetter[Brittle]())
+	assert.Nil(t, err)
+	err = g.Fill(new(BrittleHolder))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `generator_test.Brittle setter at "Part": panic: brittle`)
+}
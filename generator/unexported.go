@@ -0,0 +1,42 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// WithUnexportedFields enables filling the unexported fields of structs declared in packages matching any of the
+// given patterns, which end in "/..." to match every package below a path. At least one pattern is required, as the
+// packages whose invariants filling cannot break, such as those of the standard library, cannot be told apart from a
+// package path. A program's main package is matched by the pattern "main". Unexported fields are written with package
+// unsafe, so the patterns should be restricted to packages whose types are known to tolerate it.
+func WithUnexportedFields(pkgPatterns ...string) Option {
+	return func(g *generator) (*generator, error) {
+		if len(pkgPatterns) == 0 {
+			return nil, fmt.Errorf("WithUnexportedFields: at least one package pattern is required")
+		}
+		g.unexported = true
+		g.unexportedPkgs = append([]string(nil), pkgPatterns...)
+		return g, nil
+	}
+}
+
+// settableField returns field i of the struct value, made settable if it is unexported and in scope of
+// WithUnexportedFields.
+func (g *generator) settableField(value reflect.Value, i int) reflect.Value {
+	field := value.Field(i)
+	if field.CanSet() || !g.unexported || !value.CanAddr() || !g.fillsUnexported(value.Type().Field(i).PkgPath) {
+		return field
+	}
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+}
+
+func (g *generator) fillsUnexported(pkgPath string) bool {
+	for _, pattern := range g.unexportedPkgs {
+		if pkgPathMatches(pattern, pkgPath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package generator_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type Money struct {
+	amount   int
+	currency string
+	mu       sync.Mutex
+	at       time.Time
+	Note     string
+}
+
+func TestUnexportedFields(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithIntRange(1, 100),
+		generator.WithStringLengthRange(1, 5),
+		generator.WithUnexportedFields("github.com/merlincox/reflective/generator_test"),
+	)
+	assert.Nil(t, err)
+
+	var m Money
+	assert.Nil(t, g.Fill(&m))
+	assert.NotZero(t, m.amount)
+	assert.NotZero(t, m.currency)
+	assert.NotZero(t, m.Note)
+	assert.False(t, m.at.IsZero())
+	assert.False(t, m.at.Before(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, m.at.After(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, m.mu.TryLock())
+
+	g, err = generator.New().WithOptions(
+		generator.WithIntRange(1, 100),
+		generator.WithUnexportedFields("example.com/..."),
+	)
+	assert.Nil(t, err)
+
+	m = Money{}
+	assert.Nil(t, g.Fill(&m))
+	assert.Zero(t, m.amount)
+	assert.Zero(t, m.currency)
+
+	g, err = generator.New().WithOptions(
+		generator.WithIntRange(1, 100),
+		generator.WithUnexportedFields("github.com/merlincox/reflective/..."),
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, g.Fill(&m))
+	assert.NotZero(t, m.amount)
+
+	m = Money{}
+	assert.Nil(t, generator.New().Fill(&m))
+	assert.Zero(t, m.amount)
+
+	_, err = generator.New().WithOptions(generator.WithUnexportedFields())
+	assert.NotNil(t, err)
+}
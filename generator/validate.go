@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const defMaxAttempts = 100
+
+type checkStats struct {
+	checked  int
+	rejected int
+	// undo holds the functions reverting what has been recorded during the fill, such as entities added to the pool,
+	// so that what a rejected attempt recorded can be reverted
+	undo []func()
+}
+
+// onReject registers a function reverting something recorded while generating a value, to be called if the value, or
+// any value enclosing it, is rejected.
+func (g *generator) onReject(undo func()) {
+	if g.stats != nil {
+		g.stats.undo = append(g.stats.undo, undo)
+	}
+}
+
+// revert calls the undo functions registered since mark, in reverse order.
+func (s *checkStats) revert(mark int) {
+	for i := len(s.undo) - 1; i >= mark; i-- {
+		s.undo[i]()
+	}
+	s.undo = s.undo[:mark]
+}
+
+// WithValidator registers a validator for values of type T. A generated value which fn rejects with an error is
+// regenerated, up to the number of attempts set by WithMaxAttempts.
+func WithValidator[T any](fn func(v T) error) Option {
+	return func(g *generator) (*generator, error) {
+		rtype := reflect.TypeOf((*T)(nil)).Elem()
+		validators := make(map[reflect.Type][]func(v any) error, len(g.validators)+1)
+		for key, value := range g.validators {
+			validators[key] = value
+		}
+		checks := append([]func(v any) error(nil), g.validators[rtype]...)
+		validators[rtype] = append(checks, func(v any) error {
+			if err := fn(v.(T)); err != nil {
+				return fmt.Errorf("WithValidator[%s]: %w", rtype, err)
+			}
+			return nil
+		})
+		g.validators = validators
+		return g, nil
+	}
+}
+
+// WithFilter registers a filter for generated values of every type. A value for which fn returns false is
+// regenerated, up to the number of attempts set by WithMaxAttempts.
+func WithFilter(fn func(t *Matcher, v any) bool) Option {
+	return func(g *generator) (*generator, error) {
+		g.filters = append(append([]func(t *Matcher, v any) bool(nil), g.filters...), fn)
+		return g, nil
+	}
+}
+
+// WithMaxAttempts sets the number of times a value is generated before the fill fails when validators or filters
+// reject it. The default is 100.
+func WithMaxAttempts(n int) Option {
+	return func(g *generator) (*generator, error) {
+		if n < 1 {
+			return nil, fmt.Errorf("WithMaxAttempts: attempts must be at least 1")
+		}
+		g.maxAttempts = n
+		return g, nil
+	}
+}
+
+// checks reports whether generated values are checked by validators or filters.
+func (g *generator) checks() bool {
+	return len(g.validators) != 0 || len(g.filters) != 0
+}
+
+// fillChecked generates a value, regenerating it while validators or filters reject it.
+func (g *generator) fillChecked(value reflect.Value, matcher *Matcher) error {
+	maxAttempts := g.maxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defMaxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		var mark int
+		if g.stats != nil {
+			mark = len(g.stats.undo)
+		}
+		if err := g.fillValue(value, matcher); err != nil {
+			return err
+		}
+		if err := g.afterFill(value, matcher); err != nil {
+			return err
+		}
+		rejection, err := g.check(value, matcher)
+		if err != nil || rejection == nil {
+			return err
+		}
+		g.stats.revert(mark)
+		if attempt == maxAttempts {
+			return fmt.Errorf("no valid value at %q in %d attempts, with %d of %d values checked in the fill rejected: %w",
+				matcher.Path(), maxAttempts, g.stats.rejected, g.stats.checked, rejection)
+		}
+	}
+}
+
+// check returns the first rejection of value by a validator for its type or a filter, or nil if there is none. A
+// panic raised by a validator or filter is returned as an error.
+func (g *generator) check(value reflect.Value, matcher *Matcher) (rejection, err error) {
+	validators := g.validators[value.Type()]
+	if g.stats == nil || (len(validators) == 0 && len(g.filters) == 0) || !value.CanInterface() {
+		return nil, nil
+	}
+	v := value.Interface()
+	g.stats.checked++
+	for _, validator := range validators {
+		if err := recovered(func() error { rejection = validator(v); return nil }); err != nil {
+			return nil, fmt.Errorf("WithValidator[%s] at %q: %w", value.Type(), matcher.Path(), err)
+		}
+		if rejection != nil {
+			break
+		}
+	}
+	if rejection == nil {
+		t := matcher.forSimpleType(value.Type())
+		for i, filter := range g.filters {
+			keep := true
+			if err := recovered(func() error { keep = filter(t, v); return nil }); err != nil {
+				return nil, fmt.Errorf("WithFilter #%d at %q: %w", i+1, matcher.Path(), err)
+			}
+			if !keep {
+				rejection = fmt.Errorf("WithFilter #%d: value rejected", i+1)
+				break
+			}
+		}
+	}
+	if rejection != nil {
+		g.stats.rejected++
+	}
+	return rejection, nil
+}
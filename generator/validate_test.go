@@ -0,0 +1,117 @@
+package generator_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/merlincox/reflective/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+type Booking struct {
+	Nights int
+	Guests int
+}
+
+func (b Booking) Validate() error {
+	if b.Guests > b.Nights {
+		return errors.New("more guests than nights")
+	}
+	return nil
+}
+
+func TestValidator(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithIntRange(1, 10),
+		generator.WithValidator(Booking.Validate),
+		generator.WithFilter(func(m *generator.Matcher, v any) bool {
+			n, ok := v.(int)
+			return !ok || n%2 == 0
+		}),
+	)
+	assert.Nil(t, err)
+
+	for i := 0; i < 20; i++ {
+		var b Booking
+		assert.Nil(t, g.Fill(&b))
+		assert.Nil(t, b.Validate())
+		assert.Zero(t, b.Nights%2)
+		assert.Zero(t, b.Guests%2)
+	}
+
+	g, err = generator.New().WithOptions(
+		generator.WithMaxAttempts(3),
+		generator.WithValidator(func(b Booking) error {
+			return errors.New("never valid")
+		}),
+	)
+	assert.Nil(t, err)
+	err = g.Fill(&Booking{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `no valid value at "" in 3 attempts, with 3 of 3 values checked in the fill rejected`)
+	assert.Contains(t, err.Error(), "WithValidator[generator_test.Booking]: never valid")
+
+	_, err = generator.New().WithOptions(generator.WithMaxAttempts(0))
+	assert.NotNil(t, err)
+}
+
+type Roster struct {
+	Members  []Customer
+	Purchase Purchase
+}
+
+func TestValidatorWithReference(t *testing.T) {
+	g, err := generator.New().WithOptions(
+		generator.WithIntRange(1000, 1000000),
+		generator.WithSliceLengthRange(2, 4),
+		generator.WithEntity[Customer](),
+		generator.WithReference(
+			generator.Field(func(p *Purchase) *int { return &p.CustomerID }),
+			generator.Field(func(c *Customer) *int { return &c.ID }),
+		),
+		generator.WithValidator(func(r Roster) error {
+			if len(r.Members) != 3 {
+				return errors.New("a roster has three members")
+			}
+			return nil
+		}),
+	)
+	assert.Nil(t, err)
+
+	for i := 0; i < 10; i++ {
+		g.Pool().Reset()
+		var r Roster
+		assert.Nil(t, g.Fill(&r))
+		assert.Len(t, r.Members, 3)
+		assert.Equal(t, 3, g.Pool().Len(reflect.TypeOf(Customer{})))
+		ids := map[int]bool{}
+		for _, member := range r.Members {
+			ids[member.ID] = true
+		}
+		assert.True(t, ids[r.Purchase.CustomerID])
+	}
+}
+
+func TestValidatorPanics(t *testing.T) {
+	g, err := generator.New().WithOptions(generator.WithValidator(func(b Booking) error {
+		var bookings map[int]*Booking
+		return bookings[b.Nights].Validate()
+	}))
+	assert.Nil(t, err)
+
+	type Stay struct {
+		Booking Booking
+	}
+	err = g.Fill(&Stay{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `WithValidator[generator_test.Booking] at "Booking": panic:`)
+
+	g, err = generator.New().WithOptions(generator.WithFilter(func(m *generator.Matcher, v any) bool {
+		return v.(string) != ""
+	}))
+	assert.Nil(t, err)
+	err = g.Fill(&Stay{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `WithFilter #1 at "Booking.Nights": panic: interface conversion`)
+}
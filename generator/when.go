@@ -0,0 +1,109 @@
+package generator
+
+// Predicate reports whether a rule applies within the context described by a Matcher.
+type Predicate func(t *Matcher) bool
+
+// WithStringWhen sets the value of strings matched by p
+func WithStringWhen(p Predicate, value string) Option {
+	return func(g *generator) (*generator, error) {
+		addRule(g, &g.stringRules, matchLevel, "WithStringWhen", func(t *Matcher) (string, bool, error) {
+			return value, p(t), nil
+		})
+		return g, nil
+	}
+}
+
+// WithRunesWhen sets the runes from which strings matched by p are constructed
+func WithRunesWhen(p Predicate, runes []rune) Option {
+	return func(g *generator) (*generator, error) {
+		addRule(g, &g.runesRules, matchLevel, "WithRunesWhen", func(t *Matcher) ([]rune, bool, error) {
+			return runes, p(t), nil
+		})
+		return g, nil
+	}
+}
+
+// WithPointerNilRatioWhen sets the probability of pointers matched by p being nil
+func WithPointerNilRatioWhen(p Predicate, ratio float64) Option {
+	return ratioRule("WithPointerNilRatioWhen", matchLevel, p, ratio, pointerNilRules)
+}
+
+// WithSliceNilRatioWhen sets the probability of slices matched by p being nil
+func WithSliceNilRatioWhen(p Predicate, ratio float64) Option {
+	return ratioRule("WithSliceNilRatioWhen", matchLevel, p, ratio, sliceNilRules)
+}
+
+// WithMapNilRatioWhen sets the probability of maps matched by p being nil
+func WithMapNilRatioWhen(p Predicate, ratio float64) Option {
+	return ratioRule("WithMapNilRatioWhen", matchLevel, p, ratio, mapNilRules)
+}
+
+// WithZeroRatioWhen sets the probability of struct fields matched by p being left at their zero values
+func WithZeroRatioWhen(p Predicate, ratio float64) Option {
+	return ratioRule("WithZeroRatioWhen", matchLevel, p, ratio, zeroRules)
+}
+
+// WithBoolTrueRatioWhen sets the probability of bools matched by p being true
+func WithBoolTrueRatioWhen(p Predicate, ratio float64) Option {
+	return ratioRule("WithBoolTrueRatioWhen", matchLevel, p, ratio, boolTrueRules)
+}
+
+func WithStringLengthRangeWhen(p Predicate, min, max int) Option {
+	return rangeRule("WithStringLengthRangeWhen", matchLevel, p, stringLenInt(min), stringLenInt(max))
+}
+
+func WithSliceLengthRangeWhen(p Predicate, min, max int) Option {
+	return rangeRule("WithSliceLengthRangeWhen", matchLevel, p, sliceLenInt(min), sliceLenInt(max))
+}
+
+func WithMapLengthRangeWhen(p Predicate, min, max int) Option {
+	return rangeRule("WithMapLengthRangeWhen", matchLevel, p, mapLenInt(min), mapLenInt(max))
+}
+
+func WithIntRangeWhen(p Predicate, min, max int) Option {
+	return WithRangeWhen(p, min, max)
+}
+
+func WithInt8RangeWhen(p Predicate, min, max int8) Option {
+	return WithRangeWhen(p, min, max)
+}
+
+func WithInt16RangeWhen(p Predicate, min, max int16) Option {
+	return WithRangeWhen(p, min, max)
+}
+
+func WithInt32RangeWhen(p Predicate, min, max int32) Option {
+	return WithRangeWhen(p, min, max)
+}
+
+func WithInt64RangeWhen(p Predicate, min, max int64) Option {
+	return WithRangeWhen(p, min, max)
+}
+
+func WithUintRangeWhen(p Predicate, min, max uint) Option {
+	return WithRangeWhen(p, min, max)
+}
+
+func WithUint8RangeWhen(p Predicate, min, max uint8) Option {
+	return WithRangeWhen(p, min, max)
+}
+
+func WithUint16RangeWhen(p Predicate, min, max uint16) Option {
+	return WithRangeWhen(p, min, max)
+}
+
+func WithUint32RangeWhen(p Predicate, min, max uint32) Option {
+	return WithRangeWhen(p, min, max)
+}
+
+func WithUint64RangeWhen(p Predicate, min, max uint64) Option {
+	return WithRangeWhen(p, min, max)
+}
+
+func WithFloat32RangeWhen(p Predicate, min, max float32) Option {
+	return WithRangeWhen(p, min, max)
+}
+
+func WithFloat64RangeWhen(p Predicate, min, max float64) Option {
+	return WithRangeWhen(p, min, max)
+}